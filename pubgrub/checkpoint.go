@@ -0,0 +1,489 @@
+package pubgrub
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// checkpointVersion is the version of the checkpoint format, increased on every incompatible change
+const checkpointVersion = 2
+
+// checkpoint is the JSON form of the state of a Solver.
+// Incompatibilities are referenced by their index in Incompatibilities, which also holds those only reachable as causes.
+type checkpoint struct {
+	Version       int    `json:"version"`
+	Root          string `json:"root"`
+	DecisionLevel int    `json:"decisionLevel"`
+
+	Assignments       []checkpointAssignment      `json:"assignments"`
+	Incompatibilities []checkpointIncompatibility `json:"incompatibilities"`
+	// Active are the incompatibilities used by the search, in the order they were added
+	Active []int `json:"active"`
+
+	Phase                 string   `json:"phase"`
+	Changed               []string `json:"changed,omitempty"`
+	Contradicted          []int    `json:"contradicted,omitempty"`
+	Conflict              *int     `json:"conflict,omitempty"`
+	ConflictInPropagation bool     `json:"conflictInPropagation,omitempty"`
+
+	Statistics Statistics       `json:"statistics"`
+	Search     checkpointSearch `json:"search"`
+	Dropped    []checkpointDrop `json:"dropped,omitempty"`
+	// Selected are the versions found without the weak dependencies, absent while they are searched
+	Selected map[string]string `json:"selected,omitempty"`
+}
+
+type checkpointAssignment struct {
+	Package       string `json:"package"`
+	DecisionLevel int    `json:"decisionLevel"`
+	Decision      bool   `json:"decision,omitempty"`
+	// Version is set for decisions
+	Version string `json:"version,omitempty"`
+	// Term and Cause are set for derivations
+	Term  *checkpointTerm `json:"term,omitempty"`
+	Cause int             `json:"cause,omitempty"`
+}
+
+type checkpointTerm struct {
+	Package    string `json:"package"`
+	Constraint string `json:"constraint"`
+	Positive   bool   `json:"positive"`
+}
+
+type checkpointIncompatibility struct {
+	Kind      string           `json:"kind"`
+	Terms     []checkpointTerm `json:"terms"`
+	Causes    []int            `json:"causes,omitempty"`
+	Dependant string           `json:"dependant,omitempty"`
+	Depth     int              `json:"depth,omitempty"`
+}
+
+type checkpointSearch struct {
+	Activity              []checkpointActivity `json:"activity,omitempty"`
+	ActivityIncrement     float64              `json:"activityIncrement"`
+	ConflictsSinceReduce  int                  `json:"conflictsSinceReduce,omitempty"`
+	ConflictsSinceRestart int                  `json:"conflictsSinceRestart,omitempty"`
+	RestartInterval       float64              `json:"restartInterval,omitempty"`
+	SavedVersions         map[string]string    `json:"savedVersions,omitempty"`
+}
+
+type checkpointActivity struct {
+	Incompatibility int     `json:"incompatibility"`
+	Activity        float64 `json:"activity"`
+}
+
+type checkpointDrop struct {
+	Dependant  string `json:"dependant"`
+	Dependency string `json:"dependency"`
+	Constraint string `json:"constraint"`
+	Reason     int    `json:"reason"`
+}
+
+var incompatibilityKindNames = map[IncompatibilityKind]string{
+	IncompatibilityKindDerived:         "derived",
+	IncompatibilityKindRoot:            "root",
+	IncompatibilityKindNoVersions:      "noVersions",
+	IncompatibilityKindDependency:      "dependency",
+	IncompatibilityKindConstraint:      "constraint",
+	IncompatibilityKindVersionGroup:    "versionGroup",
+	IncompatibilityKindWeakDependency:  "weakDependency",
+	IncompatibilityKindBlockedSolution: "blockedSolution",
+	IncompatibilityKindBound:           "bound",
+	IncompatibilityKindAssumption:      "assumption",
+	IncompatibilityKindSelectedVersion: "selectedVersion",
+}
+
+var stepPhaseNames = map[stepPhase]string{
+	phasePropagate: "propagate",
+	phaseResolve:   "resolve",
+	phaseDecide:    "decide",
+}
+
+// Checkpoint serializes the state of an unfinished search to JSON, from which ResumeSolver continues it.
+// The Source and the Options are not part of the checkpoint. Constraints are stored in their canonical form.
+func (sv *Solver) Checkpoint() ([]byte, error) {
+	if sv.done {
+		return nil, errors.New("the search is done")
+	}
+	s := sv.s
+	e := checkpointEncoder{ids: map[*Incompatibility]int{}}
+
+	c := checkpoint{
+		Version:       checkpointVersion,
+		Root:          sv.rootPkg,
+		DecisionLevel: s.partialSolution.currentDecisionLevel(),
+		Phase:         stepPhaseNames[s.phase],
+		Changed:       s.propagation.changed,
+		Statistics:    s.statistics,
+		Search: checkpointSearch{
+			ActivityIncrement:     s.search.activityIncrement,
+			ConflictsSinceReduce:  s.search.conflictsSinceReduce,
+			ConflictsSinceRestart: s.search.conflictsSinceRestart,
+			RestartInterval:       s.search.restartInterval,
+		},
+	}
+
+	if sv.selected != nil {
+		c.Selected = map[string]string{}
+		for pkg, v := range sv.selected {
+			c.Selected[pkg] = versionString(v)
+		}
+	}
+	for _, in := range s.incompatibilities {
+		c.Active = append(c.Active, e.id(in))
+	}
+	for _, a := range s.partialSolution.assignments {
+		switch a := a.(type) {
+		case decision:
+			c.Assignments = append(c.Assignments, checkpointAssignment{
+				Package:       a.pkg,
+				DecisionLevel: a.decisionLevel,
+				Decision:      true,
+				Version:       versionString(a.version),
+			})
+		case derivation:
+			t := encodeTerm(a.t)
+			c.Assignments = append(c.Assignments, checkpointAssignment{
+				Package:       a.t.pkg,
+				DecisionLevel: a.decisionLevel,
+				Term:          &t,
+				Cause:         e.id(a.cause),
+			})
+		}
+	}
+	for _, in := range s.propagation.contradicted {
+		c.Contradicted = append(c.Contradicted, e.id(in))
+	}
+	if s.conflict != nil {
+		id := e.id(s.conflict)
+		c.Conflict = &id
+		c.ConflictInPropagation = s.conflictInPropagation
+	}
+
+	for in, activity := range s.search.activity {
+		c.Search.Activity = append(c.Search.Activity, checkpointActivity{Incompatibility: e.id(in), Activity: activity})
+	}
+	// Map iteration order is random, so sort for a stable output
+	slices.SortFunc(c.Search.Activity, func(a, b checkpointActivity) int {
+		return a.Incompatibility - b.Incompatibility
+	})
+	if len(s.search.savedVersions) > 0 {
+		c.Search.SavedVersions = map[string]string{}
+		for pkg, v := range s.search.savedVersions {
+			c.Search.SavedVersions[pkg] = versionString(v)
+		}
+	}
+
+	for _, d := range sv.dropped {
+		c.Dropped = append(c.Dropped, checkpointDrop{
+			Dependant:  d.Dependant,
+			Dependency: d.Dependency,
+			Constraint: d.Constraint.String(),
+			Reason:     e.id(d.Reason.Cause()),
+		})
+	}
+
+	c.Incompatibilities = e.incompatibilities
+	return json.Marshal(c) //nolint:wrapcheck
+}
+
+// ResumeSolver creates a Solver that continues the search saved by Solver.Checkpoint.
+// The source and options must describe the same problem as those of the checkpointed Solver.
+func ResumeSolver(source Source, options Options, data []byte) (*Solver, error) {
+	var c checkpoint
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, errors.Wrap(err, "failed to parse checkpoint")
+	}
+	if c.Version != checkpointVersion {
+		return nil, errors.Errorf("unsupported checkpoint version %d", c.Version)
+	}
+
+	sv := &Solver{
+		source:                  newRecordingSource(source),
+		rootPkg:                 c.Root,
+		options:                 options,
+		droppedWeakDependencies: map[weakDependency]bool{},
+	}
+	s, dropped, err := c.restore(sv.source, options)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid checkpoint")
+	}
+	if c.Selected != nil {
+		sv.selected = map[string]semver.Version{}
+		for pkg, raw := range c.Selected {
+			v, err := semver.NewVersion(raw)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid checkpoint: failed to parse selected version %s of %s", raw, pkg)
+			}
+			sv.selected[pkg] = v
+		}
+	} else {
+		s.ignoreWeakDependencies = true
+	}
+	sv.s = s
+	sv.dropped = dropped
+	for _, d := range dropped {
+		sv.droppedWeakDependencies[weakDependency{dependant: d.Dependant, dependency: d.Dependency}] = true
+	}
+	s.droppedWeakDependencies = sv.droppedWeakDependencies
+	return sv, nil
+}
+
+// checkpointEncoder numbers the incompatibilities of a checkpoint, adding each one with its causes the first time it is seen
+type checkpointEncoder struct {
+	ids               map[*Incompatibility]int
+	incompatibilities []checkpointIncompatibility
+}
+
+func (e *checkpointEncoder) id(in *Incompatibility) int {
+	if id, ok := e.ids[in]; ok {
+		return id
+	}
+	id := len(e.incompatibilities)
+	e.ids[in] = id
+	e.incompatibilities = append(e.incompatibilities, checkpointIncompatibility{})
+
+	var causes []int
+	for _, cause := range in.causes {
+		causes = append(causes, e.id(cause))
+	}
+	terms := make([]checkpointTerm, 0, len(in.terms))
+	for _, t := range in.terms {
+		terms = append(terms, encodeTerm(t))
+	}
+	slices.SortFunc(terms, func(a, b checkpointTerm) int {
+		return cmp.Compare(a.Package, b.Package)
+	})
+	e.incompatibilities[id] = checkpointIncompatibility{
+		Kind:      incompatibilityKindNames[in.kind],
+		Terms:     terms,
+		Causes:    causes,
+		Dependant: in.dependant,
+		Depth:     in.depth,
+	}
+	return id
+}
+
+func encodeTerm(t Term) checkpointTerm {
+	return checkpointTerm{
+		Package:    t.pkg,
+		Constraint: t.versionConstraint.String(),
+		Positive:   t.positive,
+	}
+}
+
+func decodeTerm(t checkpointTerm) (Term, error) {
+	constraint, err := semver.NewConstraint(t.Constraint)
+	if err != nil {
+		return Term{}, errors.Wrapf(err, "failed to parse constraint %s of %s", t.Constraint, t.Package)
+	}
+	return Term{
+		pkg:               t.Package,
+		versionConstraint: constraint,
+		positive:          t.Positive,
+	}, nil
+}
+
+// versionString returns the version as it was written, so that it is parsed back to the same version
+func versionString(v semver.Version) string {
+	if v.RawString() != "" {
+		return v.RawString()
+	}
+	return v.String()
+}
+
+// restore creates a solver in the state of the checkpoint, and returns the weak dependencies dropped before it
+func (c *checkpoint) restore(source Source, options Options) (*solver, []DroppedRecommendation, error) {
+	s, err := newSolver(source, c.Root, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// All incompatibilities are created first, so that causes can refer to any of them
+	incompatibilities := make([]*Incompatibility, len(c.Incompatibilities))
+	for i := range c.Incompatibilities {
+		incompatibilities[i] = &Incompatibility{}
+	}
+	get := func(id int) (*Incompatibility, error) {
+		if id < 0 || id >= len(incompatibilities) {
+			return nil, errors.Errorf("unknown incompatibility %d", id)
+		}
+		return incompatibilities[id], nil
+	}
+
+	for i, ci := range c.Incompatibilities {
+		in := incompatibilities[i]
+		kind, ok := incompatibilityKind(ci.Kind)
+		if !ok {
+			return nil, nil, errors.Errorf("unknown incompatibility kind %s", ci.Kind)
+		}
+		in.kind = kind
+		in.dependant = ci.Dependant
+		in.depth = ci.Depth
+		in.terms = make(map[string]Term, len(ci.Terms))
+		for _, ct := range ci.Terms {
+			t, err := decodeTerm(ct)
+			if err != nil {
+				return nil, nil, err
+			}
+			in.terms[t.pkg] = t
+		}
+		for _, id := range ci.Causes {
+			cause, err := get(id)
+			if err != nil {
+				return nil, nil, err
+			}
+			in.causes = append(in.causes, cause)
+		}
+	}
+
+	s.incompatibilities = nil
+	for _, id := range c.Active {
+		in, err := get(id)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.incompatibilities = append(s.incompatibilities, in)
+	}
+
+	for _, ca := range c.Assignments {
+		if ca.Decision {
+			v, err := semver.NewVersion(ca.Version)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to parse version %s of %s", ca.Version, ca.Package)
+			}
+			s.partialSolution.assignments = append(s.partialSolution.assignments, decision{
+				pkg:           ca.Package,
+				version:       v,
+				decisionLevel: ca.DecisionLevel,
+			})
+			continue
+		}
+		if ca.Term == nil {
+			return nil, nil, errors.Errorf("derivation of %s has no term", ca.Package)
+		}
+		t, err := decodeTerm(*ca.Term)
+		if err != nil {
+			return nil, nil, err
+		}
+		cause, err := get(ca.Cause)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.partialSolution.assignments = append(s.partialSolution.assignments, derivation{
+			t:             t,
+			cause:         cause,
+			decisionLevel: ca.DecisionLevel,
+		})
+	}
+	if level := s.partialSolution.currentDecisionLevel(); level != c.DecisionLevel {
+		return nil, nil, errors.Errorf("decision level %d does not match the %d decisions", c.DecisionLevel, level)
+	}
+
+	// The data of the decided versions is fetched again, before the statistics are restored to not count it
+	for _, a := range s.partialSolution.assignments {
+		if dec, ok := a.(decision); ok {
+			if err := s.restoreChosenVersion(dec); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	phase, ok := stepPhase(-1), false
+	for p, name := range stepPhaseNames {
+		if name == c.Phase {
+			phase, ok = p, true
+		}
+	}
+	if !ok {
+		return nil, nil, errors.Errorf("unknown phase %s", c.Phase)
+	}
+	s.phase = phase
+	s.propagation.changed = c.Changed
+	for _, id := range c.Contradicted {
+		in, err := get(id)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.propagation.contradicted = append(s.propagation.contradicted, in)
+	}
+	if c.Conflict != nil {
+		conflict, err := get(*c.Conflict)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.conflict = conflict
+		s.conflictInPropagation = c.ConflictInPropagation
+	}
+	if s.phase == phaseResolve && s.conflict == nil {
+		return nil, nil, errors.New("conflict resolution without a conflict")
+	}
+
+	s.statistics = c.Statistics
+	s.search.activityIncrement = c.Search.ActivityIncrement
+	s.search.conflictsSinceReduce = c.Search.ConflictsSinceReduce
+	s.search.conflictsSinceRestart = c.Search.ConflictsSinceRestart
+	s.search.restartInterval = c.Search.RestartInterval
+	for _, a := range c.Search.Activity {
+		in, err := get(a.Incompatibility)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.search.activity[in] = a.Activity
+	}
+	for pkg, raw := range c.Search.SavedVersions {
+		v, err := semver.NewVersion(raw)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse version %s of %s", raw, pkg)
+		}
+		s.search.savedVersions[pkg] = v
+	}
+
+	var dropped []DroppedRecommendation
+	for _, d := range c.Dropped {
+		constraint, err := semver.NewConstraint(d.Constraint)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse constraint %s of %s", d.Constraint, d.Dependency)
+		}
+		reason, err := get(d.Reason)
+		if err != nil {
+			return nil, nil, err
+		}
+		dropped = append(dropped, DroppedRecommendation{
+			Dependant:  d.Dependant,
+			Dependency: d.Dependency,
+			Constraint: constraint,
+			Reason:     SolvingError{cause: reason},
+		})
+	}
+
+	return s, dropped, nil
+}
+
+func incompatibilityKind(name string) (IncompatibilityKind, bool) {
+	for kind, kindName := range incompatibilityKindNames {
+		if kindName == name {
+			return kind, true
+		}
+	}
+	return 0, false
+}
+
+// restoreChosenVersion finds the data of the decided version in the source
+func (s *solver) restoreChosenVersion(dec decision) error {
+	versions, err := s.getPackageVersions(dec.pkg)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get versions of %s", dec.pkg)
+	}
+	for _, v := range versions {
+		if v.Version.Compare(dec.version) == 0 {
+			s.chosenVersions[dec.pkg] = v
+			return nil
+		}
+	}
+	return errors.Errorf("decided version %s of %s is not available", dec.version, dec.pkg)
+}
@@ -0,0 +1,132 @@
+package pubgrub_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub"
+	"github.com/mircearoata/pubgrub-go/pubgrub/helpers"
+	"github.com/mircearoata/pubgrub-go/pubgrub/synthetic"
+	"github.com/pkg/errors"
+)
+
+// assertResumable checkpoints the search after every step, and checks that resuming from each checkpoint
+// ends the same way as the uninterrupted search
+func assertResumable(t *testing.T, source pubgrub.Source, options pubgrub.Options) {
+	t.Helper()
+
+	uninterrupted := pubgrub.NewSolver(source, synthetic.RootPackage, options)
+	expected, expectedErr := uninterrupted.Solve()
+
+	for steps := 0; ; steps++ {
+		solver := pubgrub.NewSolver(source, synthetic.RootPackage, options)
+		for i := 0; i < steps && !solver.Done(); i++ {
+			solver.Step()
+		}
+		if solver.Done() {
+			_, err := solver.Checkpoint()
+			testza.AssertNotNil(t, err)
+			return
+		}
+
+		checkpoint, err := solver.Checkpoint()
+		testza.AssertNoError(t, err)
+
+		resumed, err := pubgrub.ResumeSolver(source, options, checkpoint)
+		testza.AssertNoError(t, err)
+		testza.AssertEqual(t, solver.DecisionLevel(), resumed.DecisionLevel())
+		testza.AssertEqual(t, len(solver.Assignments()), len(resumed.Assignments()))
+
+		// The resumed state is checkpointed exactly like the original one
+		again, err := resumed.Checkpoint()
+		testza.AssertNoError(t, err)
+		testza.AssertEqual(t, string(checkpoint), string(again))
+
+		solution, err := resumed.Solve()
+		testza.AssertEqual(t, uninterrupted.Statistics(), resumed.Statistics())
+		if expectedErr != nil {
+			var expectedSolvingErr, solvingErr pubgrub.SolvingError
+			testza.AssertTrue(t, errors.As(expectedErr, &expectedSolvingErr))
+			testza.AssertTrue(t, errors.As(err, &solvingErr))
+			testza.AssertTrue(t, sameDerivation(expectedSolvingErr.Cause(), solvingErr.Cause()))
+			continue
+		}
+		testza.AssertNoError(t, err)
+		testza.AssertEqual(t, expected.Versions, solution.Versions)
+		testza.AssertEqual(t, expected.Edges, solution.Edges)
+		testza.AssertLen(t, solution.DroppedRecommendations, len(expected.DroppedRecommendations))
+		for i, dropped := range solution.DroppedRecommendations {
+			testza.AssertEqual(t, expected.DroppedRecommendations[i].Dependency, dropped.Dependency)
+			testza.AssertEqual(t, expected.DroppedRecommendations[i].Constraint.String(), dropped.Constraint.String())
+			testza.AssertTrue(t, sameDerivation(expected.DroppedRecommendations[i].Reason.Cause(), dropped.Reason.Cause()))
+		}
+	}
+}
+
+// sameDerivation returns whether both incompatibilities have the same terms and kind, and were derived the same way
+func sameDerivation(a, b *pubgrub.Incompatibility) bool {
+	if a.Kind() != b.Kind() || len(a.Causes()) != len(b.Causes()) {
+		return false
+	}
+	if !maps.EqualFunc(termsByPackage(a), termsByPackage(b), func(t1, t2 pubgrub.Term) bool {
+		return t1.Equal(t2)
+	}) {
+		return false
+	}
+	for i := range a.Causes() {
+		if !sameDerivation(a.Causes()[i], b.Causes()[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func termsByPackage(in *pubgrub.Incompatibility) map[string]pubgrub.Term {
+	terms := map[string]pubgrub.Term{}
+	for _, t := range in.Terms() {
+		terms[t.Dependency()] = t
+	}
+	return terms
+}
+
+func TestSolver_Checkpoint(t *testing.T) {
+	t.Parallel()
+
+	source := helpers.NewMemorySource()
+	source.Package(synthetic.RootPackage).Version("1.0.0").Depends("foo", "^1.0.0").Depends("bar", "^1.0.0").Publish()
+	source.Package("foo").
+		Version("1.1.0").Depends("shared", "^1.0.0").
+		Version("1.0.0").Recommends("baz", "^1.0.0").
+		Package("bar").
+		Version("1.0.0").Depends("baz", "^2.0.0").Depends("shared", "^2.0.0").
+		Package("baz").Version("1.0.0").Version("2.0.0").
+		Package("shared").Version("1.0.0").Version("2.0.0").
+		Publish()
+	testza.AssertLen(t, source.Errors(), 0)
+
+	solution, err := pubgrub.SolveWithOptions(source, synthetic.RootPackage, pubgrub.Options{})
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "1.0.0", solution.Versions["foo"].String())
+	testza.AssertLen(t, solution.DroppedRecommendations, 1)
+	assertResumable(t, source, pubgrub.Options{})
+
+	// Failing searches, and searches restarted by the search policy, are resumed as well
+	assertResumable(t, synthetic.Pigeonhole(4, 3), pubgrub.Options{})
+	assertResumable(t, synthetic.Pigeonhole(4, 4), pubgrub.Options{SearchPolicy: aggressiveSearchPolicy})
+}
+
+func TestResumeSolver_Invalid(t *testing.T) {
+	t.Parallel()
+
+	source := synthetic.Pigeonhole(2, 2)
+
+	_, err := pubgrub.ResumeSolver(source, pubgrub.Options{}, []byte("{"))
+	testza.AssertNotNil(t, err)
+
+	_, err = pubgrub.ResumeSolver(source, pubgrub.Options{}, []byte(`{"version": 1000}`))
+	testza.AssertEqual(t, "unsupported checkpoint version 1000", err.Error())
+
+	_, err = pubgrub.ResumeSolver(source, pubgrub.Options{}, []byte(`{"version": 2, "root": "$$root$$", "active": [0], "phase": "propagate"}`))
+	testza.AssertEqual(t, "invalid checkpoint: unknown incompatibility 0", err.Error())
+}
@@ -0,0 +1,129 @@
+package pubgrub
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+type compatibilitySlotRef struct {
+	pkg  string
+	slot CompatibilitySlot
+}
+
+// CompatibilitySlotPackage returns the name under which a compatibility slot of a package is solved,
+// and under which its version is returned in the solution
+func CompatibilitySlotPackage(pkg, slot string) string {
+	return pkg + "@" + slot
+}
+
+// validateCompatibilitySlots checks that no version of a package is in more than one of its slots
+func validateCompatibilitySlots(compatibilitySlots map[string][]CompatibilitySlot) error {
+	packages := make([]string, 0, len(compatibilitySlots))
+	for pkg := range compatibilitySlots {
+		packages = append(packages, pkg)
+	}
+	slices.Sort(packages)
+	for _, pkg := range packages {
+		slots := compatibilitySlots[pkg]
+		for i, slot := range slots {
+			for _, other := range slots[i+1:] {
+				if !slot.Constraint.Intersect(other.Constraint).IsEmpty() {
+					return errors.Errorf("compatibility slots %s \"%s\" and %s \"%s\" of %s overlap", slot.Name, slot.Constraint, other.Name, other.Constraint, pkg)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *solver) indexCompatibilitySlots() {
+	s.compatibilitySlotPackages = make(map[string]compatibilitySlotRef)
+	for pkg, slots := range s.options.CompatibilitySlots {
+		for _, slot := range slots {
+			s.compatibilitySlotPackages[CompatibilitySlotPackage(pkg, slot.Name)] = compatibilitySlotRef{
+				pkg:  pkg,
+				slot: slot,
+			}
+		}
+	}
+}
+
+// withCompatibilitySlots adapts the versions of pkg returned by the Source to the compatibility slots.
+// A slot package only has the versions in its slot,
+// and a slotted package depends on the slot of each of its versions instead of having its own dependencies.
+// Dependencies on slotted packages are redirected to the slot they target.
+func (s *solver) withCompatibilitySlots(pkg string, versions []PackageVersion) []PackageVersion {
+	if len(s.options.CompatibilitySlots) == 0 {
+		return versions
+	}
+
+	ref, isSlot := s.compatibilitySlotPackages[pkg]
+	slots := s.options.CompatibilitySlots[pkg]
+
+	result := make([]PackageVersion, 0, len(versions))
+	for _, v := range versions {
+		if isSlot && !ref.slot.Constraint.Contains(v.Version) {
+			continue
+		}
+		if slot, ok := findCompatibilitySlot(slots, v.Version); ok {
+			result = append(result, PackageVersion{
+				Version: v.Version,
+				Dependencies: map[string]semver.Constraint{
+					CompatibilitySlotPackage(pkg, slot.Name): semver.SingleVersionConstraint(v.Version),
+				},
+			})
+			continue
+		}
+		v.Dependencies = s.redirectToCompatibilitySlots(v.Dependencies)
+		v.OptionalDependencies = s.redirectToCompatibilitySlots(v.OptionalDependencies)
+		v.WeakDependencies = s.redirectToCompatibilitySlots(v.WeakDependencies)
+		result = append(result, v)
+	}
+	return result
+}
+
+func (s *solver) redirectToCompatibilitySlots(deps map[string]semver.Constraint) map[string]semver.Constraint {
+	if deps == nil {
+		return nil
+	}
+	result := maps.Clone(deps)
+	for dep, constraint := range deps {
+		var target *CompatibilitySlot
+		overlapping := 0
+		for _, slot := range s.options.CompatibilitySlots[dep] {
+			slot := slot
+			if !slot.Constraint.Intersect(constraint).IsEmpty() {
+				target = &slot
+				overlapping++
+			}
+		}
+		if overlapping == 1 {
+			delete(result, dep)
+			result[CompatibilitySlotPackage(dep, target.Name)] = constraint
+		}
+	}
+	return result
+}
+
+// removeSlottedSelectors removes slotted packages whose version is already returned in its slot
+func (s *solver) removeSlottedSelectors(result map[string]semver.Version) {
+	for pkg, slots := range s.options.CompatibilitySlots {
+		if v, ok := result[pkg]; ok {
+			if _, inSlot := findCompatibilitySlot(slots, v); inSlot {
+				delete(result, pkg)
+			}
+		}
+	}
+}
+
+func findCompatibilitySlot(slots []CompatibilitySlot, v semver.Version) (CompatibilitySlot, bool) {
+	for _, slot := range slots {
+		if slot.Constraint.Contains(v) {
+			return slot, true
+		}
+	}
+	return CompatibilitySlot{}, false
+}
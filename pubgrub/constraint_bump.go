@@ -0,0 +1,131 @@
+package pubgrub
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// ConstraintBump proposes a new constraint on a direct dependency of the root,
+// allowing the newest version that can be selected together with everything else
+type ConstraintBump struct {
+	Package  string
+	Current  semver.Constraint
+	Proposed semver.Constraint
+	// Solution is the solution found with the proposed constraint
+	Solution Solution
+	// Diff is the difference between the original solution and Solution
+	Diff SolutionDiff
+}
+
+// ProposeConstraintBumps finds, for each direct dependency of the root whose constraint does not allow
+// its newest resolvable version, a new constraint allowing it. The operator of the original constraint
+// is preserved when it is a caret, tilde, lower bound or exact version, and a caret is used otherwise.
+// Each proposal is validated by solving with only that constraint changed.
+// It fails with ErrNoSource if the solution was not returned by the solver.
+func (s Solution) ProposeConstraintBumps() ([]ConstraintBump, error) {
+	source, err := s.solvedSource()
+	if err != nil {
+		return nil, err
+	}
+	requirements, err := rootRequirements(source, s.Root, s.options)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs := make([]string, 0, len(requirements))
+	for pkg := range requirements {
+		pkgs = append(pkgs, pkg)
+	}
+	slices.Sort(pkgs)
+
+	var result []ConstraintBump
+	for _, pkg := range pkgs {
+		bump, err := s.proposeConstraintBump(source, pkg, requirements)
+		if err != nil {
+			return nil, err
+		}
+		if bump != nil {
+			result = append(result, *bump)
+		}
+	}
+	return result, nil
+}
+
+func (s Solution) proposeConstraintBump(source Source, pkg string, requirements map[string]semver.Constraint) (*ConstraintBump, error) {
+	// Slotted packages are selected under their slots, so there is no single current version to bump from
+	current, ok := s.Versions[pkg]
+	if !ok {
+		return nil, nil
+	}
+	constraint := requirements[pkg]
+
+	versions, err := source.GetPackageVersions(pkg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get versions of %s", pkg)
+	}
+	candidates := make([]semver.Version, 0, len(versions))
+	for _, v := range versions {
+		if v.Version.Compare(current) > 0 && (current.IsPrerelease() || !v.Version.IsPrerelease()) {
+			candidates = append(candidates, v.Version)
+		}
+	}
+	slices.SortFunc(candidates, func(a, b semver.Version) int {
+		return b.Compare(a)
+	})
+
+	for _, candidate := range candidates {
+		if constraint.Contains(candidate) {
+			// Newer versions are not resolvable, and this one is already allowed
+			return nil, nil
+		}
+
+		proposed, err := bumpConstraint(constraint, candidate)
+		if err != nil {
+			return nil, err
+		}
+		changed := maps.Clone(requirements)
+		changed[pkg] = proposed
+		solution, err := SolveWithOptions(source, s.Root, withRootRequirements(s.options, changed))
+		if err != nil {
+			var solvingError SolvingError
+			if errors.As(err, &solvingError) {
+				continue
+			}
+			return nil, err
+		}
+		return &ConstraintBump{
+			Package:  pkg,
+			Current:  constraint,
+			Proposed: proposed,
+			Solution: solution,
+			Diff:     DiffSolutions(s, solution),
+		}, nil
+	}
+	return nil, nil
+}
+
+// bumpConstraint returns a constraint with the same operator as c, having v as its lower bound
+func bumpConstraint(c semver.Constraint, v semver.Version) (semver.Constraint, error) {
+	raw := strings.TrimSpace(c.RawString())
+	operator := "^"
+	switch {
+	case strings.Contains(raw, "||") || strings.Contains(raw, " "):
+	case strings.HasPrefix(raw, "^"), strings.HasPrefix(raw, "~"):
+		operator = raw[:1]
+	case strings.HasPrefix(raw, ">="):
+		operator = ">="
+	default:
+		if _, err := semver.NewVersion(raw); err == nil {
+			operator = ""
+		}
+	}
+	result, err := semver.NewConstraint(operator + v.String())
+	if err != nil {
+		return semver.Constraint{}, errors.Wrapf(err, "failed to bump constraint %s", raw)
+	}
+	return result, nil
+}
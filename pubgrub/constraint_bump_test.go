@@ -0,0 +1,139 @@
+package pubgrub
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+func TestSolution_ProposeConstraintBumps(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+						"bar": newConstraint("^1.0.0"),
+						"baz": newConstraint("~1.2.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^2.0.0"),
+					},
+				},
+				{
+					Version: newVersion("3.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^5.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+				{
+					Version: newVersion("3.0.0-beta.1"),
+				},
+			},
+			"baz": {
+				{
+					Version: newVersion("1.2.0"),
+				},
+				{
+					Version: newVersion("1.3.0"),
+				},
+			},
+			"qux": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("1.1.0"),
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{
+		Requirements: map[string]semver.Constraint{
+			"qux": newConstraint("1.0.0"),
+		},
+	})
+	testza.AssertNoError(t, err)
+
+	bumps, err := solution.ProposeConstraintBumps()
+	testza.AssertNoError(t, err)
+
+	proposals := map[string]string{}
+	for _, bump := range bumps {
+		proposals[bump.Package] = bump.Current.String() + " -> " + bump.Proposed.String() + ": " + bump.Diff.Summary()
+	}
+	testza.AssertEqual(t, map[string]string{
+		"bar": "^1.0.0 -> ^2.0.0: 1 major upgrade",
+		"baz": "~1.2.0 -> ~1.3.0: 1 minor upgrade",
+		"qux": "1.0.0 -> 1.1.0: 1 minor upgrade",
+	}, proposals)
+}
+
+func TestSolution_ProposeConstraintBumps_NoSource(t *testing.T) {
+	t.Parallel()
+
+	solution := Solution{
+		Root:     "$$root$$",
+		Versions: map[string]semver.Version{"foo": newVersion("1.0.0")},
+	}
+	_, err := solution.ProposeConstraintBumps()
+	testza.AssertTrue(t, errors.Is(err, ErrNoSource))
+}
+
+func TestSolution_ProposeConstraintBumps_UnsortedRootVersions(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+					},
+				},
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{})
+	testza.AssertNoError(t, err)
+
+	bumps, err := solution.ProposeConstraintBumps()
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, bumps, 1)
+	testza.AssertEqual(t, "foo", bumps[0].Package)
+}
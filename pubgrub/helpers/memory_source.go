@@ -0,0 +1,294 @@
+package helpers
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// VersionPolicy picks the version to try first among the compatible versions of a package, sorted in increasing order
+type VersionPolicy func(pkg string, versions []semver.Version) semver.Version
+
+// LatestVersionPolicy picks the latest release version, or the latest prerelease version if there are only prereleases
+func LatestVersionPolicy(_ string, versions []semver.Version) semver.Version {
+	return StandardVersionPriority(versions)
+}
+
+// OldestVersionPolicy picks the oldest version
+func OldestVersionPolicy(_ string, versions []semver.Version) semver.Version {
+	return versions[0]
+}
+
+// MemorySource is a Source of packages held in memory.
+// Versions can be published and yanked while solving, each solve seeing the versions available when it asks for them.
+type MemorySource struct {
+	mu       sync.RWMutex
+	packages map[string][]pubgrub.PackageVersion
+	yanked   map[string][]pubgrub.PackageVersion
+	policy   VersionPolicy
+	errs     []error
+}
+
+// NewMemorySource creates an empty MemorySource picking versions with LatestVersionPolicy
+func NewMemorySource() *MemorySource {
+	return &MemorySource{
+		packages: map[string][]pubgrub.PackageVersion{},
+		yanked:   map[string][]pubgrub.PackageVersion{},
+		policy:   LatestVersionPolicy,
+	}
+}
+
+func (s *MemorySource) GetPackageVersions(pkg string) ([]pubgrub.PackageVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	versions, ok := s.packages[pkg]
+	if !ok {
+		return nil, errors.Errorf("package %s not found", pkg)
+	}
+	// The stored slices are replaced, never modified, so they can be shared
+	return versions, nil
+}
+
+func (s *MemorySource) PickVersion(pkg string, versions []semver.Version) semver.Version {
+	s.mu.RLock()
+	policy := s.policy
+	s.mu.RUnlock()
+	return policy(pkg, versions)
+}
+
+// SetVersionPolicy changes how PickVersion picks versions
+func (s *MemorySource) SetVersionPolicy(policy VersionPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policy = policy
+}
+
+// Errors returns the errors of the builder calls so far, such as invalid versions and constraints
+func (s *MemorySource) Errors() []error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.errs)
+}
+
+func (s *MemorySource) addError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errs = append(s.errs, err)
+}
+
+// Publish adds a version of the package, replacing the published or yanked version with the same number if there is one
+func (s *MemorySource) Publish(pkg string, version pubgrub.PackageVersion) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publish(pkg, cloneVersion(version))
+}
+
+// Yank hides a published version of the package from the solves that have not asked for the package yet
+func (s *MemorySource) Yank(pkg string, version string) error {
+	return s.move(pkg, version, s.packages, s.yanked)
+}
+
+// Unyank makes a yanked version of the package available again
+func (s *MemorySource) Unyank(pkg string, version string) error {
+	return s.move(pkg, version, s.yanked, s.packages)
+}
+
+func (s *MemorySource) move(pkg string, version string, from, to map[string][]pubgrub.PackageVersion) error {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse version %s of %s", version, pkg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := versionIndex(from[pkg], v)
+	if i < 0 {
+		return errors.Errorf("%s %s not found", pkg, version)
+	}
+	moved := from[pkg][i]
+	from[pkg] = slices.Delete(slices.Clone(from[pkg]), i, i+1)
+	to[pkg] = withVersion(to[pkg], moved)
+	return nil
+}
+
+// update applies change to a copy of the published or yanked version of the package, or to a new version if there is none.
+// A yanked version stays yanked.
+func (s *MemorySource) update(pkg string, version semver.Version, change func(*pubgrub.PackageVersion)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := versionIndex(s.yanked[pkg], version); i >= 0 {
+		updated := cloneVersion(s.yanked[pkg][i])
+		change(&updated)
+		s.yanked[pkg] = withVersion(s.yanked[pkg], updated)
+		return
+	}
+
+	updated := pubgrub.PackageVersion{Version: version}
+	if i := versionIndex(s.packages[pkg], version); i >= 0 {
+		updated = cloneVersion(s.packages[pkg][i])
+	}
+	change(&updated)
+	s.publish(pkg, updated)
+}
+
+// publish stores the version, which must not be shared, in new slices,
+// so that the slices returned by GetPackageVersions are never modified
+func (s *MemorySource) publish(pkg string, version pubgrub.PackageVersion) {
+	s.packages[pkg] = withVersion(s.packages[pkg], version)
+	if i := versionIndex(s.yanked[pkg], version.Version); i >= 0 {
+		s.yanked[pkg] = slices.Delete(slices.Clone(s.yanked[pkg]), i, i+1)
+	}
+}
+
+func versionIndex(versions []pubgrub.PackageVersion, version semver.Version) int {
+	return slices.IndexFunc(versions, func(pv pubgrub.PackageVersion) bool {
+		return pv.Version.Compare(version) == 0
+	})
+}
+
+// withVersion returns a copy of the sorted versions with the version in order, replacing the one with the same number
+func withVersion(versions []pubgrub.PackageVersion, version pubgrub.PackageVersion) []pubgrub.PackageVersion {
+	i, found := slices.BinarySearchFunc(versions, version, func(a, b pubgrub.PackageVersion) int {
+		return a.Version.Compare(b.Version)
+	})
+	result := slices.Clone(versions)
+	if found {
+		result[i] = version
+		return result
+	}
+	return slices.Insert(result, i, version)
+}
+
+func cloneVersion(version pubgrub.PackageVersion) pubgrub.PackageVersion {
+	version.Dependencies = maps.Clone(version.Dependencies)
+	version.OptionalDependencies = maps.Clone(version.OptionalDependencies)
+	version.WeakDependencies = maps.Clone(version.WeakDependencies)
+	version.Metadata = maps.Clone(version.Metadata)
+	return version
+}
+
+// Package starts describing the package, which exists from then on, even without versions
+func (s *MemorySource) Package(name string) *PackageBuilder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.packages[name]; !ok {
+		s.packages[name] = []pubgrub.PackageVersion{}
+	}
+	return &PackageBuilder{source: s, name: name}
+}
+
+// PackageBuilder describes versions of a package of a MemorySource
+type PackageBuilder struct {
+	source *MemorySource
+	name   string
+}
+
+// Version starts describing the version of the package, which is published by Publish,
+// or when describing another version or package. Describing a published or yanked version updates it.
+// An invalid version is recorded in the Errors of the source, and the calls describing it are ignored.
+func (b *PackageBuilder) Version(version string) *VersionBuilder {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		b.source.addError(errors.Wrapf(err, "failed to parse version %s of %s", version, b.name))
+		return &VersionBuilder{PackageBuilder: b}
+	}
+	return &VersionBuilder{PackageBuilder: b, version: &v}
+}
+
+// Package starts describing another package of the same source
+func (b *PackageBuilder) Package(name string) *PackageBuilder {
+	return b.source.Package(name)
+}
+
+// VersionBuilder describes a version of a package, until it is published.
+// Its PackageBuilder methods publish it, then continue with other versions and packages.
+type VersionBuilder struct {
+	*PackageBuilder
+	version *semver.Version
+	changes []func(*pubgrub.PackageVersion)
+}
+
+// Publish publishes the version with everything described so far at once, so that no solve sees it partially described
+func (b *VersionBuilder) Publish() {
+	if b.version == nil {
+		return
+	}
+	changes := b.changes
+	b.changes = nil
+	b.source.update(b.name, *b.version, func(pv *pubgrub.PackageVersion) {
+		for _, change := range changes {
+			change(pv)
+		}
+	})
+}
+
+// Version publishes the version described so far, and starts describing another version of the package
+func (b *VersionBuilder) Version(version string) *VersionBuilder {
+	b.Publish()
+	return b.PackageBuilder.Version(version)
+}
+
+// Package publishes the version described so far, and starts describing another package of the same source
+func (b *VersionBuilder) Package(name string) *PackageBuilder {
+	b.Publish()
+	return b.PackageBuilder.Package(name)
+}
+
+// Depends adds a dependency of the version
+func (b *VersionBuilder) Depends(dep string, constraint string) *VersionBuilder {
+	return b.addDependency(dep, constraint, "dependency", func(pv *pubgrub.PackageVersion) *map[string]semver.Constraint {
+		return &pv.Dependencies
+	})
+}
+
+// Optional adds an optional dependency of the version
+func (b *VersionBuilder) Optional(dep string, constraint string) *VersionBuilder {
+	return b.addDependency(dep, constraint, "optional dependency", func(pv *pubgrub.PackageVersion) *map[string]semver.Constraint {
+		return &pv.OptionalDependencies
+	})
+}
+
+// Recommends adds a weak dependency of the version
+func (b *VersionBuilder) Recommends(dep string, constraint string) *VersionBuilder {
+	return b.addDependency(dep, constraint, "weak dependency", func(pv *pubgrub.PackageVersion) *map[string]semver.Constraint {
+		return &pv.WeakDependencies
+	})
+}
+
+// Metadata sets metadata of the version
+func (b *VersionBuilder) Metadata(key string, value any) *VersionBuilder {
+	if b.version == nil {
+		return b
+	}
+	b.changes = append(b.changes, func(pv *pubgrub.PackageVersion) {
+		if pv.Metadata == nil {
+			pv.Metadata = map[string]any{}
+		}
+		pv.Metadata[key] = value
+	})
+	return b
+}
+
+func (b *VersionBuilder) addDependency(dep string, constraint string, kind string, deps func(*pubgrub.PackageVersion) *map[string]semver.Constraint) *VersionBuilder {
+	if b.version == nil {
+		return b
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		b.source.addError(errors.Wrapf(err, "failed to parse constraint %s of the %s of %s %s on %s", constraint, kind, b.name, b.version, dep))
+		return b
+	}
+	b.changes = append(b.changes, func(pv *pubgrub.PackageVersion) {
+		m := deps(pv)
+		if *m == nil {
+			*m = map[string]semver.Constraint{}
+		}
+		(*m)[dep] = c
+	})
+	return b
+}
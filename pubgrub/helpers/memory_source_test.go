@@ -0,0 +1,211 @@
+package helpers
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+func newVersion(v string) semver.Version {
+	result, _ := semver.NewVersion(v)
+	return result
+}
+
+func TestMemorySource_Builder(t *testing.T) {
+	t.Parallel()
+
+	source := NewMemorySource()
+	source.Package("$$root$$").Version("1.0.0").Depends("foo", "^1.0.0").Depends("bar", "*").Publish()
+	source.Package("foo").
+		Version("1.0.0").Depends("baz", "^1.0.0").
+		Version("1.1.0").Depends("baz", "^2.0.0").Optional("qux", "^1.0.0").Metadata("author", "someone").
+		Version("2.0.0").
+		Package("bar").
+		Version("1.0.0").Recommends("qux", ">=2.0.0").
+		Publish()
+	source.Package("baz").Version("1.0.0").Version("2.0.0").Publish()
+	source.Package("qux")
+	testza.AssertLen(t, source.Errors(), 0)
+
+	versions, err := source.GetPackageVersions("foo")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, versions, 3)
+	testza.AssertEqual(t, "someone", versions[1].Metadata["author"])
+	testza.AssertEqual(t, "^1.0.0", versions[1].OptionalDependencies["qux"].String())
+
+	// qux is known, but has no versions
+	versions, err = source.GetPackageVersions("qux")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, versions, 0)
+	_, err = source.GetPackageVersions("unknown")
+	testza.AssertNotNil(t, err)
+
+	solution, err := pubgrub.SolveWithOptions(source, "$$root$$", pubgrub.Options{})
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, map[string]semver.Version{
+		"foo": newVersion("1.1.0"),
+		"bar": newVersion("1.0.0"),
+		"baz": newVersion("2.0.0"),
+	}, solution.Versions)
+	testza.AssertLen(t, solution.DroppedRecommendations, 1)
+}
+
+func TestMemorySource_Publish(t *testing.T) {
+	t.Parallel()
+
+	source := NewMemorySource()
+	foo := source.Package("foo").Version("1.0.0").Depends("bar", "^1.0.0")
+
+	// Nothing is published until the version is fully described
+	versions, err := source.GetPackageVersions("foo")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, versions, 0)
+
+	foo.Metadata("author", "someone").Publish()
+	versions, err = source.GetPackageVersions("foo")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, versions, 1)
+	testza.AssertEqual(t, "^1.0.0", versions[0].Dependencies["bar"].String())
+	testza.AssertEqual(t, "someone", versions[0].Metadata["author"])
+
+	// Describing the next version publishes the previous one
+	source.Package("bar").Version("1.0.0").Version("2.0.0")
+	versions, err = source.GetPackageVersions("bar")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, versions, 1)
+}
+
+func TestMemorySource_Errors(t *testing.T) {
+	t.Parallel()
+
+	source := NewMemorySource()
+	source.Package("foo").
+		Version("not a version").Depends("bar", "^1.0.0").
+		Version("1.0.0").Depends("bar", "not a constraint").Depends("baz", "^1.0.0").Optional("qux", ">>1").
+		Publish()
+
+	errs := source.Errors()
+	testza.AssertLen(t, errs, 3)
+	testza.AssertContains(t, errs[0].Error(), "failed to parse version not a version of foo")
+	testza.AssertContains(t, errs[1].Error(), "failed to parse constraint not a constraint of the dependency of foo 1.0.0 on bar")
+	testza.AssertContains(t, errs[2].Error(), "failed to parse constraint >>1 of the optional dependency of foo 1.0.0 on qux")
+
+	// The valid calls are kept
+	versions, err := source.GetPackageVersions("foo")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, versions, 1)
+	testza.AssertLen(t, versions[0].Dependencies, 1)
+	testza.AssertEqual(t, "^1.0.0", versions[0].Dependencies["baz"].String())
+}
+
+func TestMemorySource_Yank(t *testing.T) {
+	t.Parallel()
+
+	source := NewMemorySource()
+	source.Package("$$root$$").Version("1.0.0").Depends("foo", "^1.0.0").Publish()
+	source.Package("foo").Version("1.0.0").Version("1.1.0").Publish()
+
+	versions, _ := source.GetPackageVersions("foo")
+	testza.AssertNoError(t, source.Yank("foo", "1.1.0"))
+	testza.AssertNotNil(t, source.Yank("foo", "1.1.0"))
+	testza.AssertNotNil(t, source.Yank("foo", "3.0.0"))
+	// The versions returned before are not changed
+	testza.AssertLen(t, versions, 2)
+
+	result, err := pubgrub.Solve(source, "$$root$$")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, newVersion("1.0.0"), result["foo"])
+
+	testza.AssertNoError(t, source.Unyank("foo", "1.1.0"))
+	testza.AssertNotNil(t, source.Unyank("foo", "1.1.0"))
+	result, err = pubgrub.Solve(source, "$$root$$")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, newVersion("1.1.0"), result["foo"])
+
+	// Publishing a yanked version replaces it
+	testza.AssertNoError(t, source.Yank("foo", "1.0.0"))
+	source.Publish("foo", pubgrub.PackageVersion{Version: newVersion("1.0.0")})
+	testza.AssertNotNil(t, source.Unyank("foo", "1.0.0"))
+	versions, _ = source.GetPackageVersions("foo")
+	testza.AssertLen(t, versions, 2)
+}
+
+func TestMemorySource_YankedBuilder(t *testing.T) {
+	t.Parallel()
+
+	source := NewMemorySource()
+	source.Package("foo").Version("1.0.0").Version("1.1.0").Depends("bar", "^1.0.0").Publish()
+	testza.AssertNoError(t, source.Yank("foo", "1.1.0"))
+
+	// Describing a yanked version keeps it yanked, with what it had
+	source.Package("foo").Version("1.1.0").Metadata("author", "someone").Publish()
+	versions, _ := source.GetPackageVersions("foo")
+	testza.AssertLen(t, versions, 1)
+
+	testza.AssertNoError(t, source.Unyank("foo", "1.1.0"))
+	versions, _ = source.GetPackageVersions("foo")
+	testza.AssertLen(t, versions, 2)
+	testza.AssertEqual(t, "^1.0.0", versions[1].Dependencies["bar"].String())
+	testza.AssertEqual(t, "someone", versions[1].Metadata["author"])
+}
+
+func TestMemorySource_VersionPolicy(t *testing.T) {
+	t.Parallel()
+
+	source := NewMemorySource()
+	source.Package("$$root$$").Version("1.0.0").Depends("foo", "*").Publish()
+	source.Package("foo").Version("1.0.0").Version("1.1.0").Version("2.0.0-beta.1").Publish()
+
+	result, err := pubgrub.Solve(source, "$$root$$")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, newVersion("1.1.0"), result["foo"])
+
+	source.SetVersionPolicy(OldestVersionPolicy)
+	result, err = pubgrub.Solve(source, "$$root$$")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, newVersion("1.0.0"), result["foo"])
+}
+
+func TestMemorySource_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	source := NewMemorySource()
+	source.Package("$$root$$").Version("1.0.0").Depends("foo", "^1.0.0").Publish()
+	source.Package("foo").Version("1.0.0").Depends("bar", "^1.0.0").Publish()
+	source.Package("bar").Version("1.0.0").Publish()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 1; j <= 20; j++ {
+				version := fmt.Sprintf("1.%d.%d", i, j)
+				source.Package("foo").Version(version).Depends("bar", "^1.0.0").Publish()
+				source.Package("bar").Version(version).Publish()
+				if j%2 == 0 {
+					testza.AssertNoError(t, source.Yank("foo", version))
+				}
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, err := pubgrub.Solve(source, "$$root$$")
+				testza.AssertNoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	versions, err := source.GetPackageVersions("foo")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, versions, 41)
+	result, err := pubgrub.Solve(source, "$$root$$")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, newVersion("1.3.19"), result["foo"])
+}
@@ -1,9 +1,41 @@
 package pubgrub
 
+// IncompatibilityKind describes where an incompatibility comes from
+type IncompatibilityKind int
+
+const (
+	// IncompatibilityKindDerived is an incompatibility learned during conflict resolution
+	IncompatibilityKindDerived IncompatibilityKind = iota
+	// IncompatibilityKindRoot is the incompatibility requiring the root package to be selected
+	IncompatibilityKindRoot
+	// IncompatibilityKindNoVersions is an incompatibility caused by a package having no versions in a range
+	IncompatibilityKindNoVersions
+	// IncompatibilityKindDependency is an incompatibility caused by a (optional) dependency of a package
+	IncompatibilityKindDependency
+	// IncompatibilityKindConstraint is an incompatibility caused by the constraints in Options
+	IncompatibilityKindConstraint
+	// IncompatibilityKindVersionGroup is an incompatibility tying the version of a package to the version of its group
+	IncompatibilityKindVersionGroup
+	// IncompatibilityKindWeakDependency is an incompatibility caused by a weak dependency of a package
+	IncompatibilityKindWeakDependency
+	// IncompatibilityKindBlockedSolution is an incompatibility forbidding a solution that was already found
+	IncompatibilityKindBlockedSolution
+	// IncompatibilityKindBound is an incompatibility forbidding decisions that cannot lead to a better solution
+	IncompatibilityKindBound
+	// IncompatibilityKindAssumption is an incompatibility caused by an assumption of an IncrementalSolver
+	IncompatibilityKindAssumption
+	// IncompatibilityKindSelectedVersion is an incompatibility keeping the version selected without the weak dependencies
+	// while they are added
+	IncompatibilityKindSelectedVersion
+)
+
 type Incompatibility struct {
 	terms     map[string]Term
 	causes    []*Incompatibility
 	dependant string
+	kind      IncompatibilityKind
+	// depth is the depth of the derivation tree of a derived incompatibility
+	depth int
 }
 
 func (in Incompatibility) Terms() []Term {
@@ -18,6 +50,10 @@ func (in Incompatibility) Causes() []*Incompatibility {
 	return in.causes
 }
 
+func (in Incompatibility) Kind() IncompatibilityKind {
+	return in.kind
+}
+
 func (in Incompatibility) get(pkg string) *Term {
 	if t, ok := in.terms[pkg]; ok {
 		return &t
@@ -69,10 +105,12 @@ func (in Incompatibility) relation(ps *partialSolution) (setRelation, *Term) {
 	return result, &unsatisfied
 }
 
-func (in Incompatibility) makePriorCause(c *Incompatibility, satisfier string) *Incompatibility {
+func (in *Incompatibility) makePriorCause(c *Incompatibility, satisfier string) *Incompatibility {
 	newIncompatibility := &Incompatibility{
 		terms:  make(map[string]Term),
-		causes: []*Incompatibility{&in, c},
+		causes: []*Incompatibility{in, c},
+		kind:   IncompatibilityKindDerived,
+		depth:  max(in.depth, c.depth) + 1,
 	}
 	for _, t := range in.terms {
 		if t.pkg != satisfier {
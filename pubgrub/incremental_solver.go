@@ -0,0 +1,221 @@
+package pubgrub
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// Assumption is a temporary change to the problem solved by an IncrementalSolver
+type Assumption struct {
+	Package    string
+	Constraint semver.Constraint
+	// Forbidden assumptions forbid the versions in Constraint,
+	// while the others require the root to depend on Package with Constraint
+	Forbidden bool
+}
+
+func (a Assumption) String() string {
+	if a.Forbidden {
+		return fmt.Sprintf("forbid %s \"%s\"", a.Package, a.Constraint)
+	}
+	return fmt.Sprintf("require %s \"%s\"", a.Package, a.Constraint)
+}
+
+func (a Assumption) incompatibility(rootPkg string) *Incompatibility {
+	if a.Forbidden {
+		return &Incompatibility{
+			terms: map[string]Term{
+				a.Package: {
+					pkg:               a.Package,
+					versionConstraint: a.Constraint,
+					positive:          true,
+				},
+			},
+			kind: IncompatibilityKindAssumption,
+		}
+	}
+	return &Incompatibility{
+		terms: map[string]Term{
+			rootPkg: {
+				pkg:               rootPkg,
+				versionConstraint: semver.AnyConstraint,
+				positive:          true,
+			},
+			a.Package: {
+				pkg:               a.Package,
+				versionConstraint: a.Constraint,
+			},
+		},
+		dependant: rootPkg,
+		kind:      IncompatibilityKindAssumption,
+	}
+}
+
+// AssumptionConflictError is returned by IncrementalSolver.Solve when the problem has no solution under the current assumptions
+type AssumptionConflictError struct {
+	// Assumptions are the assumptions the conflict relies on, in the order they were made
+	Assumptions []Assumption
+	Cause       SolvingError
+}
+
+func (e AssumptionConflictError) Error() string {
+	return e.Cause.Error()
+}
+
+func (e AssumptionConflictError) Unwrap() error {
+	return e.Cause
+}
+
+// IncrementalSolver solves the same problem repeatedly under changing assumptions,
+// keeping the incompatibilities learned by previous solves that do not depend on any assumption.
+// Assumptions are grouped in levels, added by Push and removed with all of their assumptions by Pop.
+type IncrementalSolver struct {
+	source  Source
+	rootPkg string
+	options Options
+
+	levels  [][]Assumption
+	learned []*Incompatibility
+}
+
+func NewIncrementalSolver(source Source, rootPkg string, options Options) *IncrementalSolver {
+	return &IncrementalSolver{
+		source:  newRecordingSource(source),
+		rootPkg: rootPkg,
+		options: options,
+		levels:  [][]Assumption{nil},
+	}
+}
+
+// Push starts a new level of assumptions
+func (is *IncrementalSolver) Push() {
+	is.levels = append(is.levels, nil)
+}
+
+// Pop removes the assumptions made since the matching Push
+func (is *IncrementalSolver) Pop() error {
+	if len(is.levels) == 1 {
+		return errors.New("no assumption level to pop")
+	}
+	is.levels = is.levels[:len(is.levels)-1]
+	return nil
+}
+
+// Assume adds assumptions to the current level
+func (is *IncrementalSolver) Assume(assumptions ...Assumption) {
+	is.levels[len(is.levels)-1] = append(is.levels[len(is.levels)-1], assumptions...)
+}
+
+// Assumptions returns the assumptions of all levels, in the order they were made
+func (is *IncrementalSolver) Assumptions() []Assumption {
+	var result []Assumption
+	for _, level := range is.levels {
+		result = append(result, level...)
+	}
+	return result
+}
+
+// Solve solves the problem under the current assumptions.
+// If there is no solution because of some of the assumptions, an AssumptionConflictError is returned.
+func (is *IncrementalSolver) Solve() (Solution, error) {
+	assumptions := is.Assumptions()
+	// assumptionIndex maps the incompatibility of each assumption, in every solver, to the assumption
+	assumptionIndex := map[*Incompatibility]int{}
+	var solvers []*solver
+	solution, err := newStepwiseSolver(is.source, is.rootPkg, is.options, func(s *solver) {
+		// Assumptions are added first and never deduplicated, so that they are the ones used by the derivations
+		for i, a := range assumptions {
+			in := a.incompatibility(is.rootPkg)
+			assumptionIndex[in] = i
+			s.incompatibilities = append(s.incompatibilities, in)
+		}
+		for _, in := range is.learned {
+			s.addIncompatibility(in)
+		}
+		solvers = append(solvers, s)
+	}).Solve()
+
+	for _, s := range solvers {
+		is.learn(s)
+	}
+
+	if err != nil {
+		var solvingError SolvingError
+		if errors.As(err, &solvingError) {
+			if involved := assumptionsInDerivation(solvingError.Cause(), assumptions, assumptionIndex); len(involved) > 0 {
+				return Solution{}, AssumptionConflictError{
+					Assumptions: involved,
+					Cause:       solvingError,
+				}
+			}
+		}
+		return Solution{}, err
+	}
+	return solution, nil
+}
+
+// learn keeps the incompatibilities of s that hold regardless of the assumptions
+func (is *IncrementalSolver) learn(s *solver) {
+	memo := map[*Incompatibility]bool{}
+	for _, in := range s.incompatibilities {
+		if in.kind == IncompatibilityKindRoot || !holdsWithoutAssumptions(in, memo) {
+			continue
+		}
+		if !slices.Contains(is.learned, in) {
+			is.learned = append(is.learned, in)
+		}
+	}
+}
+
+// holdsWithoutAssumptions returns whether none of the incompatibilities the derivation of in relies on
+// is an assumption, or could be missing from another solve of the same problem
+func holdsWithoutAssumptions(in *Incompatibility, memo map[*Incompatibility]bool) bool {
+	if result, ok := memo[in]; ok {
+		return result
+	}
+	result := true
+	switch in.kind {
+	case IncompatibilityKindAssumption, IncompatibilityKindWeakDependency, IncompatibilityKindSelectedVersion, IncompatibilityKindBlockedSolution, IncompatibilityKindBound:
+		result = false
+	default:
+		for _, c := range in.causes {
+			if !holdsWithoutAssumptions(c, memo) {
+				result = false
+				break
+			}
+		}
+	}
+	memo[in] = result
+	return result
+}
+
+// assumptionsInDerivation returns the assumptions the derivation of in relies on
+func assumptionsInDerivation(in *Incompatibility, assumptions []Assumption, assumptionIndex map[*Incompatibility]int) []Assumption {
+	involved := map[int]bool{}
+	visited := map[*Incompatibility]bool{}
+	var visit func(c *Incompatibility)
+	visit = func(c *Incompatibility) {
+		if visited[c] {
+			return
+		}
+		visited[c] = true
+		if i, ok := assumptionIndex[c]; ok {
+			involved[i] = true
+		}
+		for _, cause := range c.causes {
+			visit(cause)
+		}
+	}
+	visit(in)
+
+	var result []Assumption
+	for i, a := range assumptions {
+		if involved[i] {
+			result = append(result, a)
+		}
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+package pubgrub
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+func TestIncrementalSolver(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+			"baz": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^2.0.0"),
+					},
+				},
+			},
+			"qux": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+		},
+	}
+
+	solver := NewIncrementalSolver(source, "$$root$$", Options{})
+
+	solution, err := solver.Solve()
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, map[string]semver.Version{
+		"foo": newVersion("1.0.0"),
+		"bar": newVersion("1.0.0"),
+	}, solution.Versions)
+
+	solver.Push()
+	solver.Assume(
+		Assumption{Package: "qux", Constraint: newConstraint("^1.0.0")},
+		Assumption{Package: "baz", Constraint: newConstraint("^1.0.0")},
+	)
+	_, err = solver.Solve()
+	var conflict AssumptionConflictError
+	testza.AssertTrue(t, errors.As(err, &conflict))
+	testza.AssertEqual(t, []Assumption{
+		{Package: "baz", Constraint: newConstraint("^1.0.0")},
+	}, conflict.Assumptions)
+	testza.AssertEqual(t, `Because every version of baz depends on bar "^2.0.0" and every version of foo depends on bar "^1.0.0", every version of baz forbids foo.
+So, because baz "^1.0.0" is assumed, version solving failed.`, err.Error())
+	testza.AssertNotEqual(t, 0, len(solver.learned))
+
+	testza.AssertNoError(t, solver.Pop())
+	solution, err = solver.Solve()
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, map[string]semver.Version{
+		"foo": newVersion("1.0.0"),
+		"bar": newVersion("1.0.0"),
+	}, solution.Versions)
+
+	solver.Push()
+	solver.Assume(Assumption{Package: "bar", Constraint: newConstraint("<2.0.0"), Forbidden: true})
+	_, err = solver.Solve()
+	testza.AssertTrue(t, errors.As(err, &conflict))
+	testza.AssertEqual(t, []Assumption{
+		{Package: "bar", Constraint: newConstraint("<2.0.0"), Forbidden: true},
+	}, conflict.Assumptions)
+
+	testza.AssertNoError(t, solver.Pop())
+	testza.AssertNotNil(t, solver.Pop())
+}
+
+func TestIncrementalSolver_AssumptionEqualToLearned(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+		},
+	}
+
+	solver := NewIncrementalSolver(source, "$$root$$", Options{})
+	_, err := solver.Solve()
+	testza.AssertNoError(t, err)
+
+	// The dependency of the root on foo is learned with the same terms as the first assumption
+	solver.Assume(
+		Assumption{Package: "foo", Constraint: newConstraint("^1.0.0")},
+		Assumption{Package: "foo", Constraint: newConstraint("1.0.0"), Forbidden: true},
+	)
+	_, err = solver.Solve()
+	var conflict AssumptionConflictError
+	testza.AssertTrue(t, errors.As(err, &conflict))
+	testza.AssertEqual(t, []Assumption{
+		{Package: "foo", Constraint: newConstraint("^1.0.0")},
+		{Package: "foo", Constraint: newConstraint("1.0.0"), Forbidden: true},
+	}, conflict.Assumptions)
+}
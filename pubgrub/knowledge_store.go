@@ -0,0 +1,156 @@
+package pubgrub
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// KnowledgeStore keeps the incompatibilities that hold regardless of the root package and options,
+// so that solves of different problems over the same Source do not derive them again.
+// These are the dependencies of non-root packages, the missing versions, and whatever was learned from them only.
+// Solves using version groups or compatibility slots neither use nor extend the store.
+// A KnowledgeStore is safe for concurrent use by multiple solves.
+type KnowledgeStore struct {
+	mu      sync.RWMutex
+	entries map[string]knowledgeEntry
+}
+
+type knowledgeEntry struct {
+	incompatibility *Incompatibility
+	// packages are the packages whose versions in the Source the incompatibility was derived from
+	packages []string
+}
+
+func NewKnowledgeStore() *KnowledgeStore {
+	return &KnowledgeStore{
+		entries: map[string]knowledgeEntry{},
+	}
+}
+
+// Len returns the number of incompatibilities in the store
+func (ks *KnowledgeStore) Len() int {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return len(ks.entries)
+}
+
+// Invalidate forgets everything derived from the versions of the given packages,
+// and must be called when they change in the Source
+func (ks *KnowledgeStore) Invalidate(pkgs ...string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	for key, entry := range ks.entries {
+		for _, pkg := range pkgs {
+			if slices.Contains(entry.packages, pkg) {
+				delete(ks.entries, key)
+				break
+			}
+		}
+	}
+}
+
+func usesKnowledgeStore(options Options) bool {
+	return options.KnowledgeStore != nil && len(options.VersionGroups) == 0 && len(options.CompatibilitySlots) == 0
+}
+
+// seed adds the incompatibilities of the store to s
+func (ks *KnowledgeStore) seed(s *solver) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	keys := make([]string, 0, len(ks.entries))
+	for key := range ks.entries {
+		keys = append(keys, key)
+	}
+	// Keep the order deterministic, as unit propagation depends on it
+	slices.Sort(keys)
+	for _, key := range keys {
+		s.addIncompatibility(ks.entries[key].incompatibility)
+	}
+}
+
+// learn adds the incompatibilities of s that do not depend on its root or options to the store
+func (ks *KnowledgeStore) learn(s *solver) {
+	memo := map[*Incompatibility][]string{}
+	tainted := map[*Incompatibility]bool{}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	for _, in := range s.incompatibilities {
+		packages, ok := sourcePackagesOf(in, s.rootPkg, memo, tainted)
+		if !ok {
+			continue
+		}
+		key := termsKey(in)
+		if _, exists := ks.entries[key]; !exists {
+			ks.entries[key] = knowledgeEntry{
+				incompatibility: in,
+				packages:        packages,
+			}
+		}
+	}
+}
+
+// sourcePackagesOf returns the packages whose versions the derivation of in relies on,
+// or false if the derivation relies on the root or the options
+func sourcePackagesOf(in *Incompatibility, rootPkg string, memo map[*Incompatibility][]string, tainted map[*Incompatibility]bool) ([]string, bool) {
+	if tainted[in] {
+		return nil, false
+	}
+	if packages, ok := memo[in]; ok {
+		return packages, true
+	}
+
+	var packages []string
+	ok := in.dependant != rootPkg
+	if _, hasRoot := in.terms[rootPkg]; hasRoot {
+		ok = false
+	}
+	if ok {
+		switch in.kind {
+		case IncompatibilityKindDependency:
+			packages = []string{in.dependant}
+		case IncompatibilityKindNoVersions:
+			for pkg := range in.terms {
+				packages = append(packages, pkg)
+			}
+		case IncompatibilityKindDerived:
+			for _, c := range in.causes {
+				causePackages, causeOk := sourcePackagesOf(c, rootPkg, memo, tainted)
+				if !causeOk {
+					ok = false
+					break
+				}
+				for _, pkg := range causePackages {
+					if !slices.Contains(packages, pkg) {
+						packages = append(packages, pkg)
+					}
+				}
+			}
+		default:
+			ok = false
+		}
+	}
+
+	if !ok {
+		tainted[in] = true
+		return nil, false
+	}
+	memo[in] = packages
+	return packages, true
+}
+
+// termsKey identifies an incompatibility by its terms
+func termsKey(in *Incompatibility) string {
+	parts := make([]string, 0, len(in.terms))
+	for _, t := range in.terms {
+		sign := "+"
+		if !t.positive {
+			sign = "-"
+		}
+		parts = append(parts, sign+t.pkg+" "+t.versionConstraint.String())
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,116 @@
+package pubgrub
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+func TestKnowledgeStore(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"root-a": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("*"),
+						"bar": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"root-b": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("*"),
+						"bar": newConstraint("^1.0.0"),
+						"baz": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^1.0.0"),
+					},
+				},
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^2.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+			"baz": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+					},
+				},
+			},
+		},
+	}
+
+	expected := map[string]map[string]semver.Version{
+		"root-a": {
+			"foo": newVersion("1.0.0"),
+			"bar": newVersion("1.0.0"),
+		},
+		"root-b": {
+			"foo": newVersion("1.0.0"),
+			"bar": newVersion("1.0.0"),
+			"baz": newVersion("1.0.0"),
+		},
+	}
+
+	store := NewKnowledgeStore()
+	options := Options{KnowledgeStore: store}
+
+	solution, err := SolveWithOptions(source, "root-a", options)
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, expected["root-a"], solution.Versions)
+
+	// The dependencies of foo and what was learned from them do not depend on the root
+	learned := store.Len()
+	testza.AssertNotEqual(t, 0, learned)
+	for _, entry := range store.entries {
+		_, hasRoot := entry.incompatibility.terms["root-a"]
+		testza.AssertFalse(t, hasRoot)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		root := "root-a"
+		if i%2 == 1 {
+			root = "root-b"
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			solution, err := SolveWithOptions(source, root, options)
+			testza.AssertNoError(t, err)
+			testza.AssertEqual(t, expected[root], solution.Versions)
+		}()
+	}
+	wg.Wait()
+	testza.AssertTrue(t, store.Len() >= learned)
+
+	store.Invalidate("foo")
+	for _, entry := range store.entries {
+		testza.AssertFalse(t, entry.incompatibility.dependant == "foo")
+	}
+}
@@ -0,0 +1,72 @@
+package pubgrub
+
+import (
+	"fmt"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+// Limits bounds the work done by a solve. A zero value means no limit.
+type Limits struct {
+	MaxDecisions int
+	MaxConflicts int
+	// MaxLearnedIncompatibilities limits the incompatibilities derived during conflict resolution
+	MaxLearnedIncompatibilities int
+	// MaxSourceCalls limits the calls made by the solver to get the versions of a package,
+	// including each package of a version group. Speculative prefetching of the versions is not counted.
+	MaxSourceCalls int
+	// MaxDerivationDepth limits the depth of the derivation tree of any derived incompatibility
+	MaxDerivationDepth int
+}
+
+// Statistics describes the work done by a solve
+type Statistics struct {
+	Decisions                int
+	Conflicts                int
+	LearnedIncompatibilities int
+	SourceCalls              int
+	DerivationDepth          int
+	// Restarts and ForgottenIncompatibilities count the actions of the SearchPolicy
+	Restarts                   int
+	ForgottenIncompatibilities int
+}
+
+// LimitExceededError is returned when a solve exceeds one of its Limits
+type LimitExceededError struct {
+	// Limit is the name of the exceeded limit
+	Limit      string
+	Statistics Statistics
+	// PartialSolution holds the versions decided when the limit was exceeded
+	PartialSolution map[string]semver.Version
+}
+
+func (e LimitExceededError) Error() string {
+	return fmt.Sprintf("solving exceeded the limit on %s after %d decisions, %d conflicts and %d source calls",
+		e.Limit, e.Statistics.Decisions, e.Statistics.Conflicts, e.Statistics.SourceCalls)
+}
+
+// checkLimits returns a LimitExceededError if the statistics of s exceed its limits
+func (s *solver) checkLimits() error {
+	limits := s.options.Limits
+	stats := s.statistics
+	var limit string
+	switch {
+	case limits.MaxDecisions > 0 && stats.Decisions > limits.MaxDecisions:
+		limit = "decisions"
+	case limits.MaxConflicts > 0 && stats.Conflicts > limits.MaxConflicts:
+		limit = "conflicts"
+	case limits.MaxLearnedIncompatibilities > 0 && stats.LearnedIncompatibilities > limits.MaxLearnedIncompatibilities:
+		limit = "learned incompatibilities"
+	case limits.MaxSourceCalls > 0 && stats.SourceCalls > limits.MaxSourceCalls:
+		limit = "source calls"
+	case limits.MaxDerivationDepth > 0 && stats.DerivationDepth > limits.MaxDerivationDepth:
+		limit = "derivation depth"
+	default:
+		return nil
+	}
+	return LimitExceededError{
+		Limit:           limit,
+		Statistics:      stats,
+		PartialSolution: s.versions(),
+	}
+}
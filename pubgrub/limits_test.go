@@ -0,0 +1,270 @@
+package pubgrub
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+func TestSolver_Limits(t *testing.T) {
+	t.Parallel()
+
+	// Every version of foo but the oldest depends on a version of bar that does not exist
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("*"),
+						"baz": newConstraint("*"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^2.0.0"),
+					},
+				},
+				{
+					Version: newVersion("3.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^3.0.0"),
+					},
+				},
+				{
+					Version: newVersion("4.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^4.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+			"baz": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{})
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, newVersion("1.0.0"), solution.Versions["foo"])
+	testza.AssertEqual(t, Statistics{
+		Decisions:                9,
+		Conflicts:                3,
+		LearnedIncompatibilities: 0,
+		SourceCalls:              12,
+		DerivationDepth:          0,
+	}, solution.Statistics)
+
+	tests := []struct {
+		limits Limits
+		limit  string
+	}{
+		{limits: Limits{MaxDecisions: 2}, limit: "decisions"},
+		{limits: Limits{MaxConflicts: 1}, limit: "conflicts"},
+		{limits: Limits{MaxSourceCalls: 3}, limit: "source calls"},
+	}
+	for _, test := range tests {
+		_, err := SolveWithOptions(source, "$$root$$", Options{Limits: test.limits})
+		var limitErr LimitExceededError
+		testza.AssertTrue(t, errors.As(err, &limitErr))
+		testza.AssertEqual(t, test.limit, limitErr.Limit)
+		testza.AssertNotNil(t, limitErr.PartialSolution)
+	}
+
+	_, err = SolveWithOptions(source, "$$root$$", Options{Limits: Limits{MaxConflicts: 1}})
+	testza.AssertEqual(t, "solving exceeded the limit on conflicts after 5 decisions, 2 conflicts and 7 source calls", err.Error())
+}
+
+func TestSolver_Limits_VersionGroups(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"ui-core": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"ui-core": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+			"ui-icons": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+		},
+	}
+	options := Options{
+		VersionGroups: []VersionGroup{
+			{Name: "ui", Packages: []string{"ui-core", "ui-icons"}},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", options)
+	testza.AssertNoError(t, err)
+	// The root, each package of the group, and ui-core
+	testza.AssertEqual(t, 4, solution.Statistics.SourceCalls)
+
+	options.Limits = Limits{MaxSourceCalls: 2}
+	_, err = SolveWithOptions(source, "$$root$$", options)
+	var limitErr LimitExceededError
+	testza.AssertTrue(t, errors.As(err, &limitErr))
+	testza.AssertEqual(t, "source calls", limitErr.Limit)
+}
+
+func TestSolver_Limits_Learning(t *testing.T) {
+	t.Parallel()
+
+	// The newest versions of foo and bar each depend back on an older version of themselves
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("*"),
+						"bar": newConstraint("*"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"a": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"a": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"b": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"b": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"c": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"c": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^1.0.0"),
+					},
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{})
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, 2, solution.Statistics.LearnedIncompatibilities)
+	testza.AssertEqual(t, 2, solution.Statistics.DerivationDepth)
+
+	tests := []struct {
+		limits Limits
+		limit  string
+	}{
+		{limits: Limits{MaxLearnedIncompatibilities: 1}, limit: "learned incompatibilities"},
+		{limits: Limits{MaxDerivationDepth: 1}, limit: "derivation depth"},
+	}
+	for _, test := range tests {
+		_, err := SolveWithOptions(source, "$$root$$", Options{Limits: test.limits})
+		var limitErr LimitExceededError
+		testza.AssertTrue(t, errors.As(err, &limitErr))
+		testza.AssertEqual(t, test.limit, limitErr.Limit)
+	}
+}
+
+func TestSolver_Limits_WeakDependencies(t *testing.T) {
+	t.Parallel()
+
+	// Each recommendation of bar is dropped in turn, restarting the search
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^1.0.0"),
+						"a":   newConstraint("*"),
+						"b":   newConstraint("*"),
+						"c":   newConstraint("*"),
+						"d":   newConstraint("*"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+		},
+	}
+	for _, pkg := range []string{"a", "b", "c", "d"} {
+		source.packages[pkg] = []PackageVersion{
+			{
+				Version: newVersion("1.0.0"),
+				WeakDependencies: map[string]semver.Constraint{
+					"bar": newConstraint("^2.0.0"),
+				},
+			},
+		}
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{})
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, solution.DroppedRecommendations, 4)
+	testza.AssertEqual(t, 28, solution.Statistics.Decisions)
+
+	// No single search makes more than 10 decisions, but all of them together do
+	_, err = SolveWithOptions(source, "$$root$$", Options{Limits: Limits{MaxDecisions: 10}})
+	var limitErr LimitExceededError
+	testza.AssertTrue(t, errors.As(err, &limitErr))
+	testza.AssertEqual(t, "decisions", limitErr.Limit)
+	testza.AssertEqual(t, 11, limitErr.Statistics.Decisions)
+}
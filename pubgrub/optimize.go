@@ -0,0 +1,251 @@
+package pubgrub
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// Score is the cost of a solution, compared lexicographically. Lower scores are better.
+type Score []float64
+
+func (sc Score) Compare(other Score) int {
+	return slices.Compare(sc, other)
+}
+
+// Objective scores solutions for Optimize
+type Objective interface {
+	// Bound returns the best score that any solution containing the given versions can have.
+	// When the versions are a complete solution, it returns the score of that solution.
+	Bound(versions map[string]semver.Version) (Score, error)
+}
+
+// OptimizeBudget limits the search for a better solution. Zero values mean no limit.
+type OptimizeBudget struct {
+	// MaxSolutions is the maximum number of improving solutions to find after the first one
+	MaxSolutions int
+	// MaxDecisions is the maximum number of decisions to make
+	MaxDecisions int
+}
+
+// Optimum is the best solution found by Optimize
+type Optimum struct {
+	Solution Solution
+	// Score is the score of the versions of the solution selected without the weak dependencies
+	Score Score
+	// Optimal is true when the search completed, proving that no better solution exists
+	Optimal bool
+}
+
+var errBudgetExhausted = errors.New("optimization budget exhausted")
+
+// Optimize searches for the solution with the lowest score under the objective.
+// Starting from the first solution, it keeps searching with the incompatibilities learned so far,
+// forbidding any decisions that cannot lead to a better score than the best solution found.
+// The search is done without the weak dependencies, which are then added to the best solution without changing its versions.
+func Optimize(source Source, rootPkg string, options Options, objective Objective, budget OptimizeBudget) (Optimum, error) {
+	sv := newStepwiseSolver(source, rootPkg, options, nil)
+	s, err := sv.solveWithoutWeakDependencies()
+	if err != nil {
+		return Optimum{}, err
+	}
+
+	score, err := objective.Bound(s.versions())
+	if err != nil {
+		return Optimum{}, errors.Wrap(err, "failed to score solution")
+	}
+	solution, err := sv.solveWithWeakDependencies(s)
+	if err != nil {
+		return Optimum{}, err
+	}
+	best := Optimum{
+		Solution: solution,
+		Score:    score,
+	}
+
+	s.prune = func() (*Incompatibility, error) {
+		if budget.MaxDecisions > 0 && s.statistics.Decisions > budget.MaxDecisions {
+			return nil, errBudgetExhausted
+		}
+		bound, err := objective.Bound(s.versions())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to bound score")
+		}
+		if bound.Compare(best.Score) >= 0 {
+			return s.decisionsIncompatibility(IncompatibilityKindBound), nil
+		}
+		return nil, nil
+	}
+
+	for found := 0; budget.MaxSolutions == 0 || found < budget.MaxSolutions; found++ {
+		err := s.nextSolution()
+		if err != nil {
+			if errors.Is(err, errBudgetExhausted) {
+				return best, nil
+			}
+			var solvingError SolvingError
+			if errors.As(err, &solvingError) {
+				// The whole search space is exhausted, so there is no better solution.
+				// Weak dependencies are not part of this search, so they cannot have caused the failure.
+				best.Optimal = true
+				return best, nil
+			}
+			return Optimum{}, err
+		}
+
+		// Pruning guarantees that the new solution is better than the previous best
+		best.Score, err = objective.Bound(s.versions())
+		if err != nil {
+			return Optimum{}, errors.Wrap(err, "failed to score solution")
+		}
+		best.Solution, err = sv.solveWithWeakDependencies(s)
+		if err != nil {
+			return Optimum{}, err
+		}
+	}
+
+	return best, nil
+}
+
+type newestDirectDependencies struct {
+	source   Source
+	priority []string
+	versions map[string][]semver.Version
+}
+
+// NewestDirectDependencies prefers solutions with newer versions of the given packages,
+// comparing them in the order given. Packages missing from a solution count as their newest version.
+func NewestDirectDependencies(source Source, priority []string) Objective {
+	return &newestDirectDependencies{
+		source:   source,
+		priority: priority,
+		versions: map[string][]semver.Version{},
+	}
+}
+
+func (o *newestDirectDependencies) Bound(versions map[string]semver.Version) (Score, error) {
+	score := make(Score, 0, len(o.priority))
+	for _, pkg := range o.priority {
+		available, err := o.sortedVersions(pkg)
+		if err != nil {
+			return nil, err
+		}
+		// The newest version has the lowest score
+		rank := 0
+		if v, ok := versions[pkg]; ok {
+			rank = len(available) - 1 - slices.IndexFunc(available, func(a semver.Version) bool {
+				return a.Compare(v) == 0
+			})
+		}
+		score = append(score, float64(rank))
+	}
+	return score, nil
+}
+
+func (o *newestDirectDependencies) sortedVersions(pkg string) ([]semver.Version, error) {
+	if versions, ok := o.versions[pkg]; ok {
+		return versions, nil
+	}
+	packageVersions, err := o.source.GetPackageVersions(pkg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get versions of %s", pkg)
+	}
+	versions := make([]semver.Version, 0, len(packageVersions))
+	for _, v := range packageVersions {
+		versions = append(versions, v.Version)
+	}
+	slices.SortFunc(versions, func(a, b semver.Version) int {
+		return a.Compare(b)
+	})
+	o.versions[pkg] = versions
+	return versions, nil
+}
+
+type fewestPackages struct{}
+
+// FewestPackages prefers solutions with fewer selected packages
+func FewestPackages() Objective {
+	return fewestPackages{}
+}
+
+func (fewestPackages) Bound(versions map[string]semver.Version) (Score, error) {
+	return Score{float64(len(versions))}, nil
+}
+
+type smallestTotalSize struct {
+	source Source
+	key    string
+}
+
+// SmallestTotalSize prefers solutions with the smallest sum of the numeric metadata value under key.
+// Versions without that metadata count as size 0. Negative or non-numeric sizes are an error,
+// as partial solutions are bounded by the size of their versions.
+func SmallestTotalSize(source Source, key string) Objective {
+	return smallestTotalSize{
+		source: source,
+		key:    key,
+	}
+}
+
+func (o smallestTotalSize) Bound(versions map[string]semver.Version) (Score, error) {
+	total := 0.0
+	for pkg, v := range versions {
+		packageVersions, err := o.source.GetPackageVersions(pkg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get versions of %s", pkg)
+		}
+		for _, pv := range packageVersions {
+			if pv.Version.Compare(v) == 0 {
+				size, err := metadataNumber(pv.Metadata[o.key])
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid %s of %s %s", o.key, pkg, v)
+				}
+				if size < 0 {
+					return nil, errors.Errorf("negative %s %v of %s %s", o.key, size, pkg, v)
+				}
+				total += size
+				break
+			}
+		}
+	}
+	return Score{total}, nil
+}
+
+// metadataNumber converts a numeric metadata value to a float64. A missing value is 0.
+func metadataNumber(value any) (float64, error) {
+	switch n := value.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return float64(n), nil
+	case int8:
+		return float64(n), nil
+	case int16:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint:
+		return float64(n), nil
+	case uint8:
+		return float64(n), nil
+	case uint16:
+		return float64(n), nil
+	case uint32:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	case float32:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	case json.Number:
+		f, err := n.Float64()
+		return f, errors.Wrap(err, "failed to parse number")
+	default:
+		return 0, errors.Errorf("%v is not a number", value)
+	}
+}
@@ -0,0 +1,261 @@
+package pubgrub
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+func TestOptimize_NewestDirectDependencies(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"a": newConstraint("*"),
+						"b": newConstraint("*"),
+					},
+				},
+			},
+			"a": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"b": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"b": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+		},
+	}
+
+	optimum, err := Optimize(source, "$$root$$", Options{}, NewestDirectDependencies(source, []string{"b", "a"}), OptimizeBudget{})
+	testza.AssertNoError(t, err)
+
+	expected := map[string]semver.Version{
+		"a": newVersion("1.0.0"),
+		"b": newVersion("2.0.0"),
+	}
+	testza.AssertEqual(t, expected, optimum.Solution.Versions)
+	testza.AssertEqual(t, Score{0, 1}, optimum.Score)
+	testza.AssertTrue(t, optimum.Optimal)
+}
+
+func TestOptimize_WeakDependencies(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("1.1.0"),
+					WeakDependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^2.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+		},
+	}
+
+	optimum, err := Optimize(source, "$$root$$", Options{}, NewestDirectDependencies(source, []string{"foo"}), OptimizeBudget{})
+	testza.AssertNoError(t, err)
+
+	testza.AssertEqual(t, map[string]semver.Version{"foo": newVersion("1.1.0")}, optimum.Solution.Versions)
+	testza.AssertLen(t, optimum.Solution.DroppedRecommendations, 1)
+	testza.AssertEqual(t, Score{0}, optimum.Score)
+	testza.AssertTrue(t, optimum.Optimal)
+}
+
+func TestOptimize_FewestPackages(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"x": newConstraint("*"),
+					},
+				},
+			},
+			"x": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"y": newConstraint("*"),
+					},
+				},
+				{
+					Version: newVersion("1.1.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"y": newConstraint("*"),
+						"z": newConstraint("*"),
+					},
+				},
+			},
+			"y": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+			"z": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+		},
+	}
+
+	optimum, err := Optimize(source, "$$root$$", Options{}, FewestPackages(), OptimizeBudget{})
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, map[string]semver.Version{"x": newVersion("1.1.0")}, optimum.Solution.Versions)
+	testza.AssertTrue(t, optimum.Optimal)
+
+	limited, err := Optimize(source, "$$root$$", Options{}, FewestPackages(), OptimizeBudget{MaxSolutions: 1})
+	testza.AssertNoError(t, err)
+	testza.AssertFalse(t, limited.Optimal)
+}
+
+func TestOptimize_SmallestTotalSize(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"x": newConstraint("*"),
+					},
+				},
+			},
+			"x": {
+				{
+					Version:  newVersion("1.0.0"),
+					Metadata: map[string]any{"size": 10},
+				},
+				{
+					Version:  newVersion("2.0.0"),
+					Metadata: map[string]any{"size": 30},
+				},
+				{
+					Version:  newVersion("3.0.0"),
+					Metadata: map[string]any{"size": 20},
+				},
+			},
+		},
+	}
+
+	optimum, err := Optimize(source, "$$root$$", Options{}, SmallestTotalSize(source, "size"), OptimizeBudget{})
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, map[string]semver.Version{"x": newVersion("1.0.0")}, optimum.Solution.Versions)
+	testza.AssertEqual(t, Score{10}, optimum.Score)
+	testza.AssertTrue(t, optimum.Optimal)
+}
+
+func TestOptimize_SmallestTotalSize_MetadataTypes(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"x": newConstraint("*"),
+						"y": newConstraint("*"),
+					},
+				},
+			},
+			"x": {
+				{
+					Version:  newVersion("1.0.0"),
+					Metadata: map[string]any{"size": int32(10)},
+				},
+				{
+					Version:  newVersion("2.0.0"),
+					Metadata: map[string]any{"size": json.Number("5")},
+				},
+			},
+			"y": {
+				{
+					Version:  newVersion("1.0.0"),
+					Metadata: map[string]any{"size": float32(1.5)},
+				},
+				{
+					Version:  newVersion("2.0.0"),
+					Metadata: map[string]any{"size": uint(3)},
+				},
+			},
+		},
+	}
+
+	optimum, err := Optimize(source, "$$root$$", Options{}, SmallestTotalSize(source, "size"), OptimizeBudget{})
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, map[string]semver.Version{"x": newVersion("2.0.0"), "y": newVersion("1.0.0")}, optimum.Solution.Versions)
+	testza.AssertEqual(t, Score{6.5}, optimum.Score)
+	testza.AssertTrue(t, optimum.Optimal)
+}
+
+func TestOptimize_SmallestTotalSize_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []any{-1, "large"} {
+		source := mockSource{
+			packages: map[string][]PackageVersion{
+				"$$root$$": {
+					{
+						Version: newVersion("1.0.0"),
+						Dependencies: map[string]semver.Constraint{
+							"x": newConstraint("*"),
+						},
+					},
+				},
+				"x": {
+					{
+						Version:  newVersion("1.0.0"),
+						Metadata: map[string]any{"size": size},
+					},
+				},
+			},
+		}
+
+		_, err := Optimize(source, "$$root$$", Options{}, SmallestTotalSize(source, "size"), OptimizeBudget{})
+		testza.AssertNotNil(t, err)
+	}
+}
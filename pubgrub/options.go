@@ -0,0 +1,53 @@
+package pubgrub
+
+import "github.com/mircearoata/pubgrub-go/pubgrub/semver"
+
+// Options configures a solve beyond the Source and the root package
+type Options struct {
+	// Requirements are required by the root package in addition to its own dependencies.
+	// Like those dependencies, a requirement on a slotted package targets the only slot it overlaps, if there is one.
+	Requirements map[string]semver.Constraint
+
+	// RootDependencies, if not nil, replaces the dependencies declared by the root package in the Source
+	RootDependencies map[string]semver.Constraint
+
+	// Constraints restricts the versions of a package, including those selected in its compatibility slots,
+	// only if something else depends on it, without adding the package as a dependency
+	Constraints map[string]semver.Constraint
+
+	// VersionGroups are sets of packages that must be selected at the same version, if selected at all
+	VersionGroups []VersionGroup
+
+	// CompatibilitySlots lists, for each package, the version ranges that can be selected alongside each other.
+	// Each slot is solved as a separate package named by CompatibilitySlotPackage.
+	// The slots of a package must not overlap, or solving fails.
+	CompatibilitySlots map[string][]CompatibilitySlot
+
+	// KnowledgeStore, if not nil, provides the incompatibilities learned by previous solves over the same Source,
+	// and receives those learned by this one
+	KnowledgeStore *KnowledgeStore
+
+	// Limits bounds the work done by each solve, which fails with a LimitExceededError when exceeding them
+	Limits Limits
+
+	// SearchPolicy configures forgetting learned incompatibilities and restarting the search
+	SearchPolicy SearchPolicy
+}
+
+// VersionGroup is a set of packages that are always selected at the same version.
+// The version of the whole group is decided at once, and backtracked as a unit.
+type VersionGroup struct {
+	// Name is used as the name of a virtual package holding the version of the group,
+	// so it must not be the name of a real package
+	Name     string
+	Packages []string
+}
+
+// CompatibilitySlot is a range of versions of a package that can coexist with the versions of its other slots.
+// A dependency whose constraint overlaps a single slot depends on that slot,
+// while a dependency overlapping several slots depends on the package itself,
+// which then selects the slot its chosen version belongs to.
+type CompatibilitySlot struct {
+	Name       string
+	Constraint semver.Constraint
+}
@@ -0,0 +1,210 @@
+package pubgrub
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// OutdatedPackage describes how far a selected package is from the newest available version
+type OutdatedPackage struct {
+	Package string
+	Current semver.Version
+	// Allowed is the newest version allowed by the constraints of the root, or nil if the root does not depend on the package
+	Allowed *semver.Version
+	// Latest is the newest version available in the Source.
+	// Pre-releases are only considered if the current version is a pre-release, or if there is no other version.
+	Latest semver.Version
+	// Blockers are the constraints that do not allow the latest version
+	Blockers []OutdatedBlocker
+}
+
+// OutdatedBlocker is a constraint that prevents a package from being upgraded to its latest version
+type OutdatedBlocker struct {
+	// Dependant is the package declaring the constraint, or the root for the project constraints
+	Dependant string
+	Term      Term
+	// ProjectConstraint is set if the constraint comes from Options.Constraints
+	ProjectConstraint bool
+}
+
+// IsOutdated returns whether a newer version than the current one is available
+func (p OutdatedPackage) IsOutdated() bool {
+	return p.Latest.Compare(p.Current) > 0
+}
+
+// OutdatedReport lists every selected package, sorted alphabetically
+type OutdatedReport struct {
+	Packages []OutdatedPackage
+}
+
+// Outdated compares every selected package to the versions available in the Source.
+// It fails with ErrNoSource if the solution was not returned by the solver.
+func (s Solution) Outdated() (OutdatedReport, error) {
+	packages := make([]string, 0, len(s.Versions))
+	for pkg := range s.Versions {
+		packages = append(packages, pkg)
+	}
+	slices.Sort(packages)
+
+	report := OutdatedReport{Packages: make([]OutdatedPackage, 0, len(packages))}
+	for _, pkg := range packages {
+		p, err := s.outdatedPackage(pkg)
+		if err != nil {
+			return OutdatedReport{}, err
+		}
+		report.Packages = append(report.Packages, p)
+	}
+	return report, nil
+}
+
+func (s Solution) outdatedPackage(pkg string) (OutdatedPackage, error) {
+	current := s.Versions[pkg]
+	available, err := s.availableVersions(pkg)
+	if err != nil {
+		return OutdatedPackage{}, err
+	}
+
+	result := OutdatedPackage{
+		Package: pkg,
+		Current: current,
+		Latest:  latestVersion(available, current.IsPrerelease()),
+	}
+
+	if s.IsDirect(pkg) {
+		var allowed *semver.Constraint
+		for _, e := range s.Dependants(pkg) {
+			if e.Dependant != s.Root {
+				continue
+			}
+			constraint := e.Constraint
+			if allowed != nil {
+				constraint = allowed.Intersect(constraint)
+			}
+			allowed = &constraint
+		}
+		allowedVersions := make([]semver.Version, 0, len(available))
+		for _, v := range available {
+			if allowed.Contains(v) {
+				allowedVersions = append(allowedVersions, v)
+			}
+		}
+		if len(allowedVersions) > 0 {
+			newest := latestVersion(allowedVersions, current.IsPrerelease())
+			result.Allowed = &newest
+		}
+	}
+
+	for _, e := range s.Dependants(pkg) {
+		if e.Constraint.Contains(result.Latest) {
+			continue
+		}
+		result.Blockers = append(result.Blockers, OutdatedBlocker{
+			Dependant: e.Dependant,
+			Term:      Term{pkg: pkg, versionConstraint: e.Constraint, positive: true},
+		})
+	}
+	if constraint, ok := s.options.Constraints[s.basePackage(pkg)]; ok && !constraint.Contains(result.Latest) {
+		result.Blockers = append(result.Blockers, OutdatedBlocker{
+			Dependant:         s.Root,
+			Term:              Term{pkg: pkg, versionConstraint: constraint, positive: true},
+			ProjectConstraint: true,
+		})
+	}
+	return result, nil
+}
+
+// availableVersions returns the versions of pkg in the Source, limited to its compatibility slot for slot packages
+func (s Solution) availableVersions(pkg string) ([]semver.Version, error) {
+	source, err := s.solvedSource()
+	if err != nil {
+		return nil, err
+	}
+	base := s.basePackage(pkg)
+	versions, err := source.GetPackageVersions(base)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get versions of %s", base)
+	}
+
+	var slot *CompatibilitySlot
+	for _, sl := range s.options.CompatibilitySlots[base] {
+		if CompatibilitySlotPackage(base, sl.Name) == pkg {
+			sl := sl
+			slot = &sl
+		}
+	}
+
+	result := make([]semver.Version, 0, len(versions))
+	for _, v := range versions {
+		if slot != nil && !slot.Constraint.Contains(v.Version) {
+			continue
+		}
+		result = append(result, v.Version)
+	}
+	return result, nil
+}
+
+// basePackage returns the package a compatibility slot package belongs to
+func (s Solution) basePackage(pkg string) string {
+	for base, slots := range s.options.CompatibilitySlots {
+		for _, slot := range slots {
+			if CompatibilitySlotPackage(base, slot.Name) == pkg {
+				return base
+			}
+		}
+	}
+	return pkg
+}
+
+// latestVersion returns the newest of the versions, preferring releases unless allowPrerelease is set
+func latestVersion(versions []semver.Version, allowPrerelease bool) semver.Version {
+	var latest, latestAny *semver.Version
+	for i := range versions {
+		v := &versions[i]
+		if latestAny == nil || v.Compare(*latestAny) > 0 {
+			latestAny = v
+		}
+		if (allowPrerelease || !v.IsPrerelease()) && (latest == nil || v.Compare(*latest) > 0) {
+			latest = v
+		}
+	}
+	if latest == nil {
+		latest = latestAny
+	}
+	if latest == nil {
+		return semver.Version{}
+	}
+	return *latest
+}
+
+func (r OutdatedReport) String() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "Package\tCurrent\tAllowed\tLatest\tBlocked by")
+	for _, p := range r.Packages {
+		allowed := "-"
+		if p.Allowed != nil {
+			allowed = p.Allowed.String()
+		}
+		blockers := make([]string, 0, len(p.Blockers))
+		for _, b := range p.Blockers {
+			if b.ProjectConstraint {
+				blockers = append(blockers, fmt.Sprintf("project constraints \"%s\"", b.Term.Constraint()))
+			} else {
+				blockers = append(blockers, fmt.Sprintf("%s \"%s\"", b.Dependant, b.Term.Constraint()))
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Package, p.Current, allowed, p.Latest, strings.Join(blockers, ", "))
+	}
+	_ = w.Flush()
+
+	lines := strings.Split(strings.TrimRight(sb.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
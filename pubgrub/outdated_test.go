@@ -0,0 +1,179 @@
+package pubgrub
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+func TestSolution_Outdated(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+						"bar": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("2.0.0"),
+				},
+				{
+					Version: newVersion("1.4.0"),
+					Dependencies: map[string]semver.Constraint{
+						"baz": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0-beta.1"),
+				},
+			},
+			"baz": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("1.2.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{
+		Constraints: map[string]semver.Constraint{
+			"baz": newConstraint("<1.2.0"),
+		},
+	})
+	testza.AssertNoError(t, err)
+
+	report, err := solution.Outdated()
+	testza.AssertNoError(t, err)
+
+	allowedFoo := newVersion("1.4.0")
+	allowedBar := newVersion("1.0.0")
+	expected := OutdatedReport{
+		Packages: []OutdatedPackage{
+			{
+				Package: "bar",
+				Current: newVersion("1.0.0"),
+				Allowed: &allowedBar,
+				Latest:  newVersion("1.0.0"),
+			},
+			{
+				Package: "baz",
+				Current: newVersion("1.0.0"),
+				Latest:  newVersion("2.0.0"),
+				Blockers: []OutdatedBlocker{
+					{Dependant: "foo", Term: Term{pkg: "baz", versionConstraint: newConstraint("^1.0.0"), positive: true}},
+					{Dependant: "$$root$$", Term: Term{pkg: "baz", versionConstraint: newConstraint("<1.2.0"), positive: true}, ProjectConstraint: true},
+				},
+			},
+			{
+				Package: "foo",
+				Current: newVersion("1.4.0"),
+				Allowed: &allowedFoo,
+				Latest:  newVersion("2.0.0"),
+				Blockers: []OutdatedBlocker{
+					{Dependant: "$$root$$", Term: Term{pkg: "foo", versionConstraint: newConstraint("^1.0.0"), positive: true}},
+				},
+			},
+		},
+	}
+	testza.AssertEqual(t, expected, report)
+	testza.AssertFalse(t, report.Packages[0].IsOutdated())
+	testza.AssertTrue(t, report.Packages[1].IsOutdated())
+
+	expectedText := `Package  Current  Allowed  Latest  Blocked by
+bar      1.0.0    1.0.0    1.0.0
+baz      1.0.0    -        2.0.0   foo "^1.0.0", project constraints "<1.2.0"
+foo      1.4.0    1.4.0    2.0.0   $$root$$ "^1.0.0"`
+	testza.AssertEqual(t, expectedText, report.String())
+}
+
+func TestSolution_Outdated_CompatibilitySlots(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^2.0.0"),
+						"bar": newConstraint("*"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("1.1.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+				{
+					Version: newVersion("2.1.0"),
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{
+		Constraints: map[string]semver.Constraint{
+			"foo": newConstraint("<2.1.0"),
+		},
+		CompatibilitySlots: map[string][]CompatibilitySlot{
+			"foo": {
+				{Name: "1", Constraint: newConstraint("^1.0.0")},
+				{Name: "2", Constraint: newConstraint("^2.0.0")},
+			},
+		},
+	})
+	testza.AssertNoError(t, err)
+
+	report, err := solution.Outdated()
+	testza.AssertNoError(t, err)
+	expectedText := `Package  Current  Allowed  Latest  Blocked by
+bar      1.0.0    1.0.0    1.0.0
+foo@1    1.1.0    -        1.1.0
+foo@2    2.0.0    2.1.0    2.1.0   project constraints "<2.1.0"`
+	testza.AssertEqual(t, expectedText, report.String())
+}
+
+func TestSolution_Outdated_NoSource(t *testing.T) {
+	t.Parallel()
+
+	solution := Solution{
+		Root:     "$$root$$",
+		Versions: map[string]semver.Version{"foo": newVersion("1.0.0")},
+	}
+	_, err := solution.Outdated()
+	testza.AssertTrue(t, errors.Is(err, ErrNoSource))
+}
@@ -67,6 +67,15 @@ func (ps *partialSolution) get(pkg string) *Term {
 	return result
 }
 
+func (ps *partialSolution) isDecided(pkg string) bool {
+	for _, a := range ps.assignments {
+		if _, ok := a.(decision); ok && a.Package() == pkg {
+			return true
+		}
+	}
+	return false
+}
+
 func (ps *partialSolution) currentDecisionLevel() int {
 	currentDecisionLevel := 0
 	for _, a := range ps.assignments {
@@ -0,0 +1,48 @@
+package pubgrub
+
+import (
+	"sync"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+// recordingSource remembers the versions returned by a Source,
+// so that further solves of the same problem do not fetch them again
+type recordingSource struct {
+	source   Source
+	mu       sync.RWMutex
+	versions map[string][]PackageVersion
+}
+
+func newRecordingSource(source Source) *recordingSource {
+	if recording, ok := source.(*recordingSource); ok {
+		return recording
+	}
+	return &recordingSource{
+		source:   source,
+		versions: map[string][]PackageVersion{},
+	}
+}
+
+func (s *recordingSource) GetPackageVersions(pkg string) ([]PackageVersion, error) {
+	s.mu.RLock()
+	versions, ok := s.versions[pkg]
+	s.mu.RUnlock()
+	if ok {
+		return versions, nil
+	}
+
+	versions, err := s.source.GetPackageVersions(pkg)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	s.mu.Lock()
+	s.versions[pkg] = versions
+	s.mu.Unlock()
+	return versions, nil
+}
+
+func (s *recordingSource) PickVersion(pkg string, versions []semver.Version) semver.Version {
+	return s.source.PickVersion(pkg, versions)
+}
@@ -0,0 +1,358 @@
+package pubgrub
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// bruteForceSolve tries every combination of versions of the packages of a tiny registry,
+// each package being either not selected or selected at one of its versions.
+// It returns the first combination in which the root is selected and every dependency is satisfied, if there is one.
+func bruteForceSolve(source mockSource, rootPkg string) (map[string]semver.Version, bool) {
+	packages := make([]string, 0, len(source.packages))
+	for pkg := range source.packages {
+		packages = append(packages, pkg)
+	}
+	slices.Sort(packages)
+
+	// choice[i] is the index of the version of packages[i], or -1 if it is not selected
+	choice := make([]int, len(packages))
+	for i, pkg := range packages {
+		choice[i] = -1
+		if pkg == rootPkg {
+			choice[i] = 0
+		}
+	}
+
+	for {
+		selected := map[string]PackageVersion{}
+		for i, pkg := range packages {
+			if choice[i] >= 0 {
+				selected[pkg] = source.packages[pkg][choice[i]]
+			}
+		}
+		if _, ok := selected[rootPkg]; ok && satisfiesDependencies(selected) {
+			result := map[string]semver.Version{}
+			for pkg, v := range selected {
+				if pkg != rootPkg {
+					result[pkg] = v.Version
+				}
+			}
+			return result, true
+		}
+
+		// Next combination, as if counting with each package as a digit
+		i := 0
+		for ; i < len(packages); i++ {
+			if packages[i] == rootPkg {
+				continue
+			}
+			choice[i]++
+			if choice[i] < len(source.packages[packages[i]]) {
+				break
+			}
+			choice[i] = -1
+		}
+		if i == len(packages) {
+			return nil, false
+		}
+	}
+}
+
+func satisfiesDependencies(selected map[string]PackageVersion) bool {
+	for _, v := range selected {
+		for dep, constraint := range v.Dependencies {
+			depVersion, ok := selected[dep]
+			if !ok || !constraint.Contains(depVersion.Version) {
+				return false
+			}
+		}
+		for dep, constraint := range v.OptionalDependencies {
+			depVersion, ok := selected[dep]
+			if ok && !constraint.Contains(depVersion.Version) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestBruteForceSolve(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("*"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^2.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+		},
+	}
+
+	result, ok := bruteForceSolve(source, "$$root$$")
+	testza.AssertTrue(t, ok)
+	testza.AssertEqual(t, map[string]semver.Version{"foo": newVersion("1.0.0")}, result)
+
+	source.packages["foo"] = source.packages["foo"][1:]
+	_, ok = bruteForceSolve(source, "$$root$$")
+	testza.AssertFalse(t, ok)
+}
+
+var (
+	randomRegistryVersions    = []string{"1.0.0", "1.1.0", "2.0.0"}
+	randomRegistryConstraints = []string{"*", "^1.0.0", "^2.0.0", ">=1.1.0", "<2.0.0", "1.0.0", "1.1.0", "2.0.0", ">=3.0.0"}
+)
+
+// randomRegistry generates a registry of a few packages, small enough to be solved by bruteForceSolve
+func randomRegistry(r *rand.Rand) mockSource {
+	names := []string{"a", "b", "c", "d", "e"}[:2+r.Intn(4)]
+	randomDependencies := func(pkg string, probability float64) map[string]semver.Constraint {
+		var deps map[string]semver.Constraint
+		for _, dep := range names {
+			if dep == pkg || r.Float64() >= probability {
+				continue
+			}
+			if deps == nil {
+				deps = map[string]semver.Constraint{}
+			}
+			deps[dep] = newConstraint(randomRegistryConstraints[r.Intn(len(randomRegistryConstraints))])
+		}
+		return deps
+	}
+
+	packages := map[string][]PackageVersion{
+		"$$root$$": {
+			{
+				Version:      newVersion("1.0.0"),
+				Dependencies: randomDependencies("$$root$$", 0.5),
+			},
+		},
+	}
+	for _, pkg := range names {
+		packages[pkg] = []PackageVersion{}
+		for _, v := range randomRegistryVersions {
+			if r.Float64() < 0.25 {
+				continue
+			}
+			packages[pkg] = append(packages[pkg], PackageVersion{
+				Version:              newVersion(v),
+				Dependencies:         randomDependencies(pkg, 0.3),
+				OptionalDependencies: randomDependencies(pkg, 0.1),
+			})
+		}
+	}
+	return mockSource{packages: packages}
+}
+
+// differentialCheck solves the registry with both solvers, and describes how they disagree, if they do
+func differentialCheck(source mockSource) string {
+	var failure string
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				failure = fmt.Sprintf("panic: %v", r)
+			}
+		}()
+		failure = compareSolvers(source)
+	}()
+	return failure
+}
+
+// compareSolvers describes how the solutions of both solvers for the registry disagree, if they do
+func compareSolvers(source mockSource) string {
+	expected, satisfiable := bruteForceSolve(source, "$$root$$")
+	solution, err := SolveWithOptions(source, "$$root$$", Options{})
+	if err != nil {
+		var solvingError SolvingError
+		if !errors.As(err, &solvingError) {
+			return fmt.Sprintf("unexpected error: %s", err)
+		}
+		if satisfiable {
+			return fmt.Sprintf("no solution found, but %v is one:\n%s", expected, err)
+		}
+		return ""
+	}
+	if !satisfiable {
+		return fmt.Sprintf("found %v, but there is no solution", solution.Versions)
+	}
+	violations, err := Verify(source, "$$root$$", solution.Versions)
+	if err != nil {
+		return fmt.Sprintf("failed to verify %v: %s", solution.Versions, err)
+	}
+	if len(violations) > 0 {
+		return fmt.Sprintf("found %v, which has violations %v", solution.Versions, violations)
+	}
+	return ""
+}
+
+// shrinkRegistry removes packages, versions and dependencies from a failing registry,
+// and widens its constraints, for as long as it keeps failing
+func shrinkRegistry(source mockSource, fails func(mockSource) bool) mockSource {
+	for shrunk := true; shrunk; {
+		shrunk = false
+		for _, candidate := range registryShrinks(source) {
+			if fails(candidate) {
+				source = candidate
+				shrunk = true
+				break
+			}
+		}
+	}
+	return source
+}
+
+// registryShrinks returns every registry one step simpler than source
+func registryShrinks(source mockSource) []mockSource {
+	packages := sortedPackages(source)
+	var result []mockSource
+
+	for _, pkg := range packages {
+		if pkg == "$$root$$" {
+			continue
+		}
+		candidate := cloneRegistry(source)
+		delete(candidate.packages, pkg)
+		for _, versions := range candidate.packages {
+			for _, v := range versions {
+				delete(v.Dependencies, pkg)
+				delete(v.OptionalDependencies, pkg)
+			}
+		}
+		result = append(result, candidate)
+	}
+
+	for _, pkg := range packages {
+		for i, v := range source.packages[pkg] {
+			if pkg != "$$root$$" {
+				candidate := cloneRegistry(source)
+				candidate.packages[pkg] = slices.Delete(candidate.packages[pkg], i, i+1)
+				result = append(result, candidate)
+			}
+
+			for _, deps := range []func(PackageVersion) map[string]semver.Constraint{
+				func(v PackageVersion) map[string]semver.Constraint { return v.Dependencies },
+				func(v PackageVersion) map[string]semver.Constraint { return v.OptionalDependencies },
+			} {
+				for _, dep := range sortedKeys(deps(v)) {
+					candidate := cloneRegistry(source)
+					delete(deps(candidate.packages[pkg][i]), dep)
+					result = append(result, candidate)
+
+					if !deps(v)[dep].IsAny() {
+						candidate := cloneRegistry(source)
+						deps(candidate.packages[pkg][i])[dep] = semver.AnyConstraint
+						result = append(result, candidate)
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+func cloneRegistry(source mockSource) mockSource {
+	packages := make(map[string][]PackageVersion, len(source.packages))
+	for pkg, versions := range source.packages {
+		packages[pkg] = make([]PackageVersion, len(versions))
+		for i, v := range versions {
+			packages[pkg][i] = PackageVersion{
+				Version:              v.Version,
+				Dependencies:         cloneDependencies(v.Dependencies),
+				OptionalDependencies: cloneDependencies(v.OptionalDependencies),
+			}
+		}
+	}
+	return mockSource{packages: packages}
+}
+
+func cloneDependencies(deps map[string]semver.Constraint) map[string]semver.Constraint {
+	result := make(map[string]semver.Constraint, len(deps))
+	for dep, constraint := range deps {
+		result[dep] = constraint
+	}
+	return result
+}
+
+func sortedPackages(source mockSource) []string {
+	packages := make([]string, 0, len(source.packages))
+	for pkg := range source.packages {
+		packages = append(packages, pkg)
+	}
+	slices.Sort(packages)
+	return packages
+}
+
+func sortedKeys(deps map[string]semver.Constraint) []string {
+	keys := make([]string, 0, len(deps))
+	for dep := range deps {
+		keys = append(keys, dep)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// formatRegistry writes a registry one version per line, optional dependencies being marked with a ?
+func formatRegistry(source mockSource) string {
+	var sb strings.Builder
+	for _, pkg := range sortedPackages(source) {
+		if len(source.packages[pkg]) == 0 {
+			fmt.Fprintf(&sb, "%s: no versions\n", pkg)
+		}
+		for _, v := range source.packages[pkg] {
+			var deps []string
+			for _, dep := range sortedKeys(v.Dependencies) {
+				deps = append(deps, fmt.Sprintf("%s %s", dep, v.Dependencies[dep]))
+			}
+			for _, dep := range sortedKeys(v.OptionalDependencies) {
+				deps = append(deps, fmt.Sprintf("?%s %s", dep, v.OptionalDependencies[dep]))
+			}
+			fmt.Fprintf(&sb, "%s %s: %s\n", pkg, v.Version, strings.Join(deps, ", "))
+		}
+	}
+	return sb.String()
+}
+
+func TestSolver_Differential(t *testing.T) {
+	t.Parallel()
+
+	iterations := 1000
+	if testing.Short() {
+		iterations = 100
+	}
+	for seed := int64(0); seed < int64(iterations); seed++ {
+		source := randomRegistry(rand.New(rand.NewSource(seed))) //nolint:gosec
+		if failure := differentialCheck(source); failure != "" {
+			minimal := shrinkRegistry(source, func(candidate mockSource) bool {
+				return differentialCheck(candidate) != ""
+			})
+			t.Fatalf("seed %d: %s\nminimal failing registry:\n%s%s", seed, failure, formatRegistry(minimal), differentialCheck(minimal))
+		}
+	}
+}
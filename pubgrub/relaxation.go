@@ -0,0 +1,227 @@
+package pubgrub
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// Relaxation is a change to a single root requirement that makes a failing problem solvable
+type Relaxation struct {
+	Package string
+	Current semver.Constraint
+	// Proposed is the relaxed constraint, or nil if the requirement is removed
+	Proposed *semver.Constraint
+	// Solution is the solution found with the relaxed requirement
+	Solution Solution
+
+	// addedVersions is the number of versions of the package the relaxed constraint allows in addition to the current one
+	addedVersions int
+}
+
+func (r Relaxation) String() string {
+	if r.Proposed == nil {
+		return fmt.Sprintf("remove %s", r.Package)
+	}
+	return fmt.Sprintf("relax your constraint on %s from \"%s\" to \"%s\"", r.Package, r.Current, r.Proposed)
+}
+
+// SuggestRelaxations proposes changes to the root requirements involved in the derivation of solvingError,
+// each of which is verified to make the problem solvable.
+// For each package, the smallest widening of its constraint towards the available versions that resolves is proposed,
+// and removing the requirement is proposed if no widening resolves.
+// Suggestions are ordered by the number of versions they add, with removals last.
+func SuggestRelaxations(source Source, rootPkg string, options Options, solvingError SolvingError) ([]Relaxation, error) {
+	source = newRecordingSource(source)
+
+	requirements, err := rootRequirements(source, rootPkg, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Relaxation
+	for _, pkg := range rootTermsInDerivation(solvingError.Cause(), rootPkg) {
+		if _, ok := requirements[pkg]; !ok {
+			continue
+		}
+		relaxation, err := relaxRequirement(source, rootPkg, options, requirements, pkg)
+		if err != nil {
+			return nil, err
+		}
+		if relaxation != nil {
+			result = append(result, *relaxation)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b Relaxation) int {
+		if (a.Proposed == nil) != (b.Proposed == nil) {
+			if a.Proposed == nil {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(a.addedVersions, b.addedVersions)
+	})
+	return result, nil
+}
+
+// rootTermsInDerivation returns, in alphabetical order, the packages the root depends on
+// in the dependency incompatibilities the derivation of in relies on
+func rootTermsInDerivation(in *Incompatibility, rootPkg string) []string {
+	found := map[string]bool{}
+	visited := map[*Incompatibility]bool{}
+	var visit func(c *Incompatibility)
+	visit = func(c *Incompatibility) {
+		if visited[c] {
+			return
+		}
+		visited[c] = true
+		if c.Kind() == IncompatibilityKindDependency && c.dependant == rootPkg {
+			for _, t := range c.Terms() {
+				if t.Dependency() != rootPkg {
+					found[t.Dependency()] = true
+				}
+			}
+		}
+		for _, cause := range c.Causes() {
+			visit(cause)
+		}
+	}
+	visit(in)
+
+	result := make([]string, 0, len(found))
+	for pkg := range found {
+		result = append(result, pkg)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// relaxRequirement tries the relaxed constraints of the requirement on pkg, from the smallest to the widest,
+// and then removing it, returning the first change that makes the problem solvable
+func relaxRequirement(source Source, rootPkg string, options Options, requirements map[string]semver.Constraint, pkg string) (*Relaxation, error) {
+	current := requirements[pkg]
+
+	versions, err := source.GetPackageVersions(pkg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get versions of %s", pkg)
+	}
+	available := make([]semver.Version, 0, len(versions))
+	for _, v := range versions {
+		if !v.Version.IsPrerelease() {
+			available = append(available, v.Version)
+		}
+	}
+	// Among relaxations adding as many versions, prefer those towards newer versions
+	slices.SortFunc(available, func(a, b semver.Version) int {
+		return b.Compare(a)
+	})
+
+	for _, candidate := range relaxedConstraints(current, available) {
+		changed := maps.Clone(requirements)
+		changed[pkg] = candidate.constraint
+		solution, err := SolveWithOptions(source, rootPkg, withRootRequirements(options, changed))
+		if err != nil {
+			var solvingError SolvingError
+			if errors.As(err, &solvingError) {
+				continue
+			}
+			return nil, err
+		}
+		proposed := candidate.constraint
+		return &Relaxation{
+			Package:       pkg,
+			Current:       current,
+			Proposed:      &proposed,
+			Solution:      solution,
+			addedVersions: candidate.addedVersions,
+		}, nil
+	}
+
+	changed := maps.Clone(requirements)
+	delete(changed, pkg)
+	solution, err := SolveWithOptions(source, rootPkg, withRootRequirements(options, changed))
+	if err != nil {
+		var solvingError SolvingError
+		if errors.As(err, &solvingError) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &Relaxation{
+		Package:  pkg,
+		Current:  current,
+		Solution: solution,
+	}, nil
+}
+
+type relaxedConstraint struct {
+	constraint    semver.Constraint
+	addedVersions int
+}
+
+// relaxedConstraints returns the constraints of the form ">=L <U" that widen current to include one more available version,
+// where L is the lowest version included and U is the caret upper bound of the highest one.
+// They are sorted by the number of available versions they add.
+func relaxedConstraints(current semver.Constraint, available []semver.Version) []relaxedConstraint {
+	var allowed []semver.Version
+	for _, v := range available {
+		if current.Contains(v) {
+			allowed = append(allowed, v)
+		}
+	}
+
+	seen := map[string]bool{}
+	var result []relaxedConstraint
+	for _, v := range available {
+		if current.Contains(v) {
+			continue
+		}
+		lowest, highest := v, v
+		for _, a := range allowed {
+			if a.Compare(lowest) < 0 {
+				lowest = a
+			}
+			if a.Compare(highest) > 0 {
+				highest = a
+			}
+		}
+		constraint, err := semver.NewConstraint(fmt.Sprintf(">=%s <%s", lowest, caretUpperBound(highest)))
+		if err != nil {
+			continue
+		}
+		constraint = constraint.Union(current)
+		if seen[constraint.String()] {
+			continue
+		}
+		seen[constraint.String()] = true
+
+		added := 0
+		for _, a := range available {
+			if constraint.Contains(a) && !current.Contains(a) {
+				added++
+			}
+		}
+		result = append(result, relaxedConstraint{constraint: constraint, addedVersions: added})
+	}
+
+	slices.SortStableFunc(result, func(a, b relaxedConstraint) int {
+		return cmp.Compare(a.addedVersions, b.addedVersions)
+	})
+	return result
+}
+
+// caretUpperBound returns the lowest version not allowed by a caret constraint on v
+func caretUpperBound(v semver.Version) string {
+	switch {
+	case v.Major() != 0:
+		return fmt.Sprintf("%d.0.0", v.Major()+1)
+	case v.Minor() != 0:
+		return fmt.Sprintf("0.%d.0", v.Minor()+1)
+	}
+	return fmt.Sprintf("0.0.%d", v.Patch()+1)
+}
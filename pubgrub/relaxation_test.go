@@ -0,0 +1,96 @@
+package pubgrub
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+func TestSuggestRelaxations(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+						"bar": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+				{
+					Version: newVersion("2.1.0"),
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^2.0.0"),
+					},
+				},
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"qux": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^3.0.0"),
+					},
+				},
+			},
+		},
+	}
+
+	_, err := SolveWithOptions(source, "$$root$$", Options{})
+	var solvingError SolvingError
+	testza.AssertTrue(t, errors.As(err, &solvingError))
+
+	relaxations, err := SuggestRelaxations(source, "$$root$$", Options{}, solvingError)
+	testza.AssertNoError(t, err)
+	suggestions := make([]string, len(relaxations))
+	for i, r := range relaxations {
+		suggestions[i] = r.String()
+	}
+	testza.AssertEqual(t, []string{
+		`relax your constraint on bar from "^1.0.0" to ">=1.0.0 <3.0.0"`,
+		`relax your constraint on foo from "^1.0.0" to ">=1.0.0 <3.0.0"`,
+	}, suggestions)
+	testza.AssertEqual(t, newVersion("2.0.0"), relaxations[0].Solution.Versions["bar"])
+	testza.AssertEqual(t, newVersion("2.1.0"), relaxations[1].Solution.Versions["foo"])
+
+	options := Options{
+		RootDependencies: map[string]semver.Constraint{
+			"foo": newConstraint("^1.0.0"),
+			"qux": newConstraint("^1.0.0"),
+		},
+	}
+	_, err = SolveWithOptions(source, "$$root$$", options)
+	testza.AssertTrue(t, errors.As(err, &solvingError))
+
+	relaxations, err = SuggestRelaxations(source, "$$root$$", options, solvingError)
+	testza.AssertNoError(t, err)
+	suggestions = make([]string, len(relaxations))
+	for i, r := range relaxations {
+		suggestions[i] = r.String()
+	}
+	testza.AssertEqual(t, []string{
+		`remove qux`,
+	}, suggestions)
+}
@@ -0,0 +1,67 @@
+package pubgrub
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// withDependencies returns copies of the versions, all having the given dependencies
+func withDependencies(versions []PackageVersion, dependencies map[string]semver.Constraint) []PackageVersion {
+	result := make([]PackageVersion, len(versions))
+	for i, v := range versions {
+		v.Dependencies = dependencies
+		result[i] = v
+	}
+	return result
+}
+
+// rootRequirements returns everything the root requires: the dependencies of the root version the solver picks,
+// or Options.RootDependencies if set, combined with Options.Requirements
+func rootRequirements(source Source, root string, options Options) (map[string]semver.Constraint, error) {
+	result := maps.Clone(options.RootDependencies)
+	if result == nil {
+		versions, err := source.GetPackageVersions(root)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get versions of %s", root)
+		}
+		if len(versions) == 0 {
+			return nil, errors.Errorf("no versions of %s", root)
+		}
+		allVersions := make([]semver.Version, len(versions))
+		for i, v := range versions {
+			allVersions[i] = v.Version
+		}
+		slices.SortFunc(allVersions, func(a, b semver.Version) int {
+			return a.Compare(b)
+		})
+		picked := source.PickVersion(root, allVersions)
+		for _, v := range versions {
+			if v.Version.Compare(picked) == 0 {
+				result = maps.Clone(v.Dependencies)
+				break
+			}
+		}
+		if result == nil {
+			result = map[string]semver.Constraint{}
+		}
+	}
+
+	for pkg, constraint := range options.Requirements {
+		if existing, ok := result[pkg]; ok {
+			constraint = existing.Intersect(constraint)
+		}
+		result[pkg] = constraint
+	}
+	delete(result, root)
+	return result, nil
+}
+
+// withRootRequirements returns options in which the root requires exactly the given requirements
+func withRootRequirements(options Options, requirements map[string]semver.Constraint) Options {
+	options.RootDependencies = requirements
+	options.Requirements = nil
+	return options
+}
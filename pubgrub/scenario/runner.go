@@ -0,0 +1,65 @@
+package scenario
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Extension is the extension of the scenario files run by RunDir
+const Extension = ".scenario"
+
+// RunDir runs each scenario file of the directory as a subtest.
+// If update is set, the expected outcome of the files is replaced by the actual one instead,
+// which is meant to be done with a flag of the test, such as go test -update.
+func RunDir(t *testing.T, dir string, update bool) {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+Extension))
+	if err != nil {
+		t.Fatalf("failed to list the scenarios of %s: %s", dir, err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no scenarios in %s", dir)
+	}
+	for _, file := range files {
+		file := file
+		t.Run(strings.TrimSuffix(filepath.Base(file), Extension), func(t *testing.T) {
+			t.Parallel()
+			RunFile(t, file, update)
+		})
+	}
+}
+
+// RunFile runs the scenario file, failing the test if the outcome is not the expected one.
+// If update is set, the expected outcome of the file is replaced by the actual one instead.
+func RunFile(t *testing.T, file string, update bool) {
+	t.Helper()
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read %s: %s", file, err)
+	}
+	s, err := Parse(string(data))
+	if err != nil {
+		t.Fatalf("failed to parse %s: %s", file, err)
+	}
+	outcome, err := s.Run()
+	if err != nil {
+		t.Fatalf("%s: %s", file, err)
+	}
+	outcome = strings.TrimSpace(outcome) + "\n"
+
+	if update {
+		if outcome != s.Expected {
+			if err := os.WriteFile(file, []byte(s.WithOutcome(outcome)), 0o644); err != nil { //nolint:gosec
+				t.Fatalf("failed to update %s: %s", file, err)
+			}
+		}
+		return
+	}
+	if outcome != s.Expected {
+		t.Errorf("%s: unexpected outcome\nexpected:\n%s\nactual:\n%s", file, s.Expected, outcome)
+	}
+}
@@ -0,0 +1,313 @@
+// Package scenario reads resolution problems and their expected outcome from plain-text scenario files.
+//
+// A scenario file is made of sections, each starting with a header line in brackets.
+// The input sections describe the problem:
+//
+//	[registry]
+//	foo 1.0.0
+//	    depends bar ^1.0.0
+//	    optional baz ^1.0.0
+//	    recommends docs *
+//	bar 1.0.0
+//	baz
+//
+//	[root]
+//	foo ^1.0.0
+//
+//	[constraints]
+//	bar <2.0.0
+//
+//	[groups]
+//	ui ui-core ui-icons
+//
+//	[slots]
+//	foo 1 ^1.0.0
+//
+// Each version of the registry is listed with its dependencies indented below it,
+// and a package without versions is listed by its name alone.
+// The root section lists the dependencies of the root package, the others fill the Options of the same name.
+// Lines of the input starting with # are comments.
+//
+// The expected outcome follows the input, either as the solution and the dropped recommendations:
+//
+//	[solution]
+//	bar 1.0.0
+//	foo 1.0.0
+//
+//	[dropped foo docs *]
+//	Because ...
+//
+// or as the exact error text:
+//
+//	[error]
+//	Because ...
+//
+// A scenario without an expected outcome fails, and gets the actual outcome written by the update flag of RunDir.
+package scenario
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub"
+	"github.com/mircearoata/pubgrub-go/pubgrub/helpers"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// RootPackage is the name of the root package of the scenarios
+const RootPackage = "$$root$$"
+
+// Scenario is a resolution problem and its expected outcome
+type Scenario struct {
+	Source  *helpers.MemorySource
+	Options pubgrub.Options
+	// Expected is the expected outcome, formatted like the result of Run
+	Expected string
+
+	// input is the text of the input sections, kept to write the file again with another outcome
+	input string
+}
+
+// Parse reads a scenario file
+func Parse(data string) (*Scenario, error) {
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+
+	// The expected outcome starts at the first expectation section,
+	// and is left empty for a new scenario, so that it fails until its outcome is written with the update flag
+	expectedStart := slices.IndexFunc(lines, func(line string) bool {
+		header, ok := sectionHeader(line)
+		return ok && isExpectation(header)
+	})
+	if expectedStart < 0 {
+		expectedStart = len(lines)
+	}
+
+	rootVersion, err := semver.NewVersion("1.0.0")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the root version")
+	}
+	p := &parser{
+		source: helpers.NewMemorySource(),
+		root: pubgrub.PackageVersion{
+			Version:      rootVersion,
+			Dependencies: map[string]semver.Constraint{},
+		},
+	}
+	for i, line := range lines[:expectedStart] {
+		if err := p.parseLine(line); err != nil {
+			return nil, errors.Wrapf(err, "line %d", i+1)
+		}
+	}
+	p.flushVersion()
+	p.source.Publish(RootPackage, p.root)
+
+	expected := strings.TrimSpace(strings.Join(lines[expectedStart:], "\n"))
+	if expected != "" {
+		expected += "\n"
+	}
+	for i, line := range lines[expectedStart:] {
+		header, ok := sectionHeader(line)
+		if ok && !isExpectation(header) {
+			return nil, errors.Errorf("line %d: unexpected section %s after the expected outcome", expectedStart+i+1, header)
+		}
+	}
+
+	return &Scenario{
+		Source:   p.source,
+		Options:  p.options,
+		Expected: expected,
+		input:    strings.Join(lines[:expectedStart], "\n"),
+	}, nil
+}
+
+// WithOutcome returns the text of the scenario file, with the outcome replacing the expected one
+func (s *Scenario) WithOutcome(outcome string) string {
+	return strings.TrimRight(s.input, "\n") + "\n\n" + outcome
+}
+
+func sectionHeader(line string) (string, bool) {
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(strings.TrimRight(line, " \t"), "]") {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimRight(line, " \t")[1:], "]"), true
+}
+
+func isExpectation(header string) bool {
+	return header == "solution" || header == "error" || strings.HasPrefix(header, "dropped ")
+}
+
+type parser struct {
+	source  *helpers.MemorySource
+	root    pubgrub.PackageVersion
+	options pubgrub.Options
+
+	section string
+	// version is the registry version whose dependencies are being read
+	pkg     string
+	version *pubgrub.PackageVersion
+}
+
+func (p *parser) parseLine(line string) error {
+	if header, ok := sectionHeader(line); ok {
+		p.flushVersion()
+		switch header {
+		case "registry", "root", "constraints", "groups", "slots":
+			p.section = header
+			return nil
+		}
+		return errors.Errorf("unknown section %s", header)
+	}
+
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return nil
+	}
+	fields := strings.Fields(trimmed)
+
+	switch p.section {
+	case "registry":
+		return p.parseRegistryLine(fields, line[0] == ' ' || line[0] == '\t')
+	case "root":
+		constraint, err := parseConstraint(fields, 1)
+		if err != nil {
+			return err
+		}
+		p.root.Dependencies[fields[0]] = constraint
+	case "constraints":
+		constraint, err := parseConstraint(fields, 1)
+		if err != nil {
+			return err
+		}
+		if p.options.Constraints == nil {
+			p.options.Constraints = map[string]semver.Constraint{}
+		}
+		p.options.Constraints[fields[0]] = constraint
+	case "groups":
+		if len(fields) < 2 {
+			return errors.New("a group needs a name and packages")
+		}
+		p.options.VersionGroups = append(p.options.VersionGroups, pubgrub.VersionGroup{Name: fields[0], Packages: fields[1:]})
+	case "slots":
+		constraint, err := parseConstraint(fields, 2)
+		if err != nil {
+			return err
+		}
+		if p.options.CompatibilitySlots == nil {
+			p.options.CompatibilitySlots = map[string][]pubgrub.CompatibilitySlot{}
+		}
+		p.options.CompatibilitySlots[fields[0]] = append(p.options.CompatibilitySlots[fields[0]], pubgrub.CompatibilitySlot{
+			Name:       fields[1],
+			Constraint: constraint,
+		})
+	default:
+		return errors.New("line outside of a section")
+	}
+	return nil
+}
+
+func (p *parser) parseRegistryLine(fields []string, indented bool) error {
+	if !indented {
+		p.flushVersion()
+		p.source.Package(fields[0])
+		if len(fields) == 1 {
+			return nil
+		}
+		if len(fields) > 2 {
+			return errors.New("a version is a package name and a version number")
+		}
+		v, err := semver.NewVersion(fields[1])
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse version %s of %s", fields[1], fields[0])
+		}
+		p.pkg = fields[0]
+		p.version = &pubgrub.PackageVersion{Version: v}
+		return nil
+	}
+
+	if p.version == nil {
+		return errors.New("dependency outside of a version")
+	}
+	if len(fields) < 2 {
+		return errors.New("a dependency is a kind, a package name and a constraint")
+	}
+	constraint, err := parseConstraint(fields[1:], 1)
+	if err != nil {
+		return err
+	}
+	var deps *map[string]semver.Constraint
+	switch fields[0] {
+	case "depends":
+		deps = &p.version.Dependencies
+	case "optional":
+		deps = &p.version.OptionalDependencies
+	case "recommends":
+		deps = &p.version.WeakDependencies
+	default:
+		return errors.Errorf("unknown dependency kind %s, expected depends, optional or recommends", fields[0])
+	}
+	if *deps == nil {
+		*deps = map[string]semver.Constraint{}
+	}
+	(*deps)[fields[1]] = constraint
+	return nil
+}
+
+func (p *parser) flushVersion() {
+	if p.version != nil {
+		p.source.Publish(p.pkg, *p.version)
+		p.version = nil
+	}
+}
+
+// parseConstraint parses the fields after the first n fields as a constraint
+func parseConstraint(fields []string, n int) (semver.Constraint, error) {
+	if len(fields) <= n {
+		return semver.Constraint{}, errors.New("missing constraint")
+	}
+	raw := strings.Join(fields[n:], " ")
+	constraint, err := semver.NewConstraint(raw)
+	if err != nil {
+		return semver.Constraint{}, errors.Wrapf(err, "failed to parse constraint %s", raw)
+	}
+	return constraint, nil
+}
+
+// Run solves the scenario and formats the outcome like the expected outcome is written.
+// Only a SolvingError is an outcome; any other error, or a solution not satisfying the scenario, is returned as an error.
+func (s *Scenario) Run() (string, error) {
+	solution, err := pubgrub.SolveWithOptions(s.Source, RootPackage, s.Options)
+	if err != nil {
+		var solvingError pubgrub.SolvingError
+		if !errors.As(err, &solvingError) {
+			return "", err //nolint:wrapcheck
+		}
+		return fmt.Sprintf("[error]\n%s\n", err.Error()), nil
+	}
+
+	if len(s.Options.CompatibilitySlots) == 0 {
+		violations, err := pubgrub.VerifyWithOptions(s.Source, RootPackage, solution.Versions, s.Options)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to verify the solution")
+		}
+		if len(violations) > 0 {
+			return "", errors.Errorf("invalid solution %v: %v", solution.Versions, violations)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("[solution]\n")
+	packages := make([]string, 0, len(solution.Versions))
+	for pkg := range solution.Versions {
+		packages = append(packages, pkg)
+	}
+	slices.Sort(packages)
+	for _, pkg := range packages {
+		fmt.Fprintf(&sb, "%s %s\n", pkg, solution.Versions[pkg])
+	}
+	for _, dropped := range solution.DroppedRecommendations {
+		fmt.Fprintf(&sb, "\n[dropped %s %s %s]\n%s\n", dropped.Dependant, dropped.Dependency, dropped.Constraint, dropped.Reason.Error())
+	}
+	return sb.String(), nil
+}
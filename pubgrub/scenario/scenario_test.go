@@ -0,0 +1,71 @@
+package scenario
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+var update = flag.Bool("update", false, "replace the expected outcome of the scenarios by the actual one")
+
+func TestScenarios(t *testing.T) {
+	t.Parallel()
+
+	RunDir(t, "testdata", *update)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse("[registry]\nfoo 1.0.0\n    depends bar ^1.0.0\nbar 1.0.0\n\n[root]\nfoo *\n")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "", s.Expected)
+
+	outcome, err := s.Run()
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "[solution]\nbar 1.0.0\nfoo 1.0.0\n", outcome)
+	testza.AssertEqual(t, "[registry]\nfoo 1.0.0\n    depends bar ^1.0.0\nbar 1.0.0\n\n[root]\nfoo *\n\n"+outcome, s.WithOutcome(outcome))
+
+	again, err := Parse(s.WithOutcome(outcome))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, outcome, again.Expected)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for data, expected := range map[string]string{
+		"foo 1.0.0\n":                                       "line 1: line outside of a section",
+		"[unknown]\n":                                       "line 1: unknown section unknown",
+		"[registry]\nfoo 1.0.0 2.0.0\n":                     "line 2: a version is a package name and a version number",
+		"[registry]\nfoo latest\n":                          "line 2: failed to parse version latest of foo",
+		"[registry]\n    depends bar *\n":                   "line 2: dependency outside of a version",
+		"[registry]\nfoo 1.0.0\n    requires bar *\n":       "line 3: unknown dependency kind requires",
+		"[root]\nfoo\n":                                     "line 2: missing constraint",
+		"[root]\nfoo >>1\n":                                 "line 2: failed to parse constraint >>1",
+		"[groups]\nui\n":                                    "line 2: a group needs a name and packages",
+		"[root]\nfoo *\n\n[error]\nfailed\n\n[constraints]": "line 7: unexpected section constraints after the expected outcome",
+	} {
+		_, err := Parse(data)
+		testza.AssertNotNil(t, err, data)
+		if err != nil {
+			testza.AssertContains(t, err.Error(), expected)
+		}
+	}
+}
+
+func TestRun_Error(t *testing.T) {
+	t.Parallel()
+
+	// Overlapping slots are not a failure of the solve, so they are not an outcome
+	s, err := Parse("[registry]\nfoo 1.0.0\n\n[root]\nfoo *\n\n[slots]\nfoo 1 ^1.0.0\nfoo 1-2 >=1.5.0 <3.0.0\n")
+	testza.AssertNoError(t, err)
+
+	outcome, err := s.Run()
+	testza.AssertEqual(t, "", outcome)
+	testza.AssertNotNil(t, err)
+	if err != nil {
+		testza.AssertContains(t, err.Error(), "overlap")
+	}
+}
@@ -0,0 +1,174 @@
+package pubgrub
+
+import (
+	"math"
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+// SearchPolicy enables forgetting learned incompatibilities and restarting the search, which can help on large problems.
+// A zero value disables both, so that every learned incompatibility is kept and the search never restarts.
+type SearchPolicy struct {
+	// ReduceInterval is the number of conflicts between two reductions of the learned incompatibilities
+	ReduceInterval int
+	// KeepLearned is the number of learned incompatibilities kept by a reduction, besides those currently in use.
+	// The incompatibilities involved in the most recent conflicts are kept.
+	KeepLearned int
+
+	// RestartInterval is the number of conflicts before the first restart
+	RestartInterval int
+	// RestartGrowth multiplies the number of conflicts between restarts after each restart, 1.5 if not greater than 1
+	RestartGrowth float64
+}
+
+const (
+	activityDecay        = 0.95
+	defaultRestartGrowth = 1.5
+)
+
+// searchState holds the data used by the SearchPolicy
+type searchState struct {
+	activity          map[*Incompatibility]float64
+	activityIncrement float64
+
+	conflictsSinceReduce  int
+	conflictsSinceRestart int
+	restartInterval       float64
+
+	// savedVersions holds the latest version decided for each package, which is preferred again after a restart
+	savedVersions map[string]semver.Version
+}
+
+func (s *solver) searchPolicyEnabled() bool {
+	policy := s.options.SearchPolicy
+	return policy.ReduceInterval > 0 || policy.RestartInterval > 0
+}
+
+func (s *solver) initSearchState() {
+	s.search = searchState{
+		activity:          map[*Incompatibility]float64{},
+		activityIncrement: 1,
+		restartInterval:   float64(s.options.SearchPolicy.RestartInterval),
+		savedVersions:     map[string]semver.Version{},
+	}
+}
+
+// bumpActivity marks a learned incompatibility as involved in the current conflict
+func (s *solver) bumpActivity(in *Incompatibility) {
+	if !s.searchPolicyEnabled() || in.kind != IncompatibilityKindDerived {
+		return
+	}
+	s.search.activity[in] += s.search.activityIncrement
+	if s.search.activity[in] > 1e100 {
+		for k := range s.search.activity {
+			s.search.activity[k] *= 1e-100
+		}
+		s.search.activityIncrement *= 1e-100
+	}
+}
+
+// onConflict decays the activity of all learned incompatibilities, by increasing the activity of the following conflicts
+func (s *solver) onConflict() {
+	if !s.searchPolicyEnabled() {
+		return
+	}
+	s.search.activityIncrement /= activityDecay
+	s.search.conflictsSinceReduce++
+	s.search.conflictsSinceRestart++
+}
+
+// applySearchPolicy reduces the learned incompatibilities and restarts the search when they are due.
+// It returns whether the search was restarted.
+func (s *solver) applySearchPolicy() bool {
+	if !s.searchPolicyEnabled() {
+		return false
+	}
+	policy := s.options.SearchPolicy
+
+	if policy.ReduceInterval > 0 && s.search.conflictsSinceReduce >= policy.ReduceInterval {
+		s.search.conflictsSinceReduce = 0
+		s.reduceLearned(policy.KeepLearned)
+	}
+
+	if policy.RestartInterval > 0 && float64(s.search.conflictsSinceRestart) >= s.search.restartInterval {
+		s.search.conflictsSinceRestart = 0
+		growth := policy.RestartGrowth
+		if growth <= 1 {
+			growth = defaultRestartGrowth
+		}
+		s.search.restartInterval = math.Ceil(s.search.restartInterval * growth)
+		s.restart()
+		return true
+	}
+	return false
+}
+
+// reduceLearned forgets the least active learned incompatibilities, except the keep most active ones
+// and those that are the cause of a current derivation
+func (s *solver) reduceLearned(keep int) {
+	locked := map[*Incompatibility]bool{}
+	for _, a := range s.partialSolution.assignments {
+		if der, ok := a.(derivation); ok {
+			locked[der.cause] = true
+		}
+	}
+
+	var candidates []*Incompatibility
+	for _, in := range s.incompatibilities {
+		if in.kind == IncompatibilityKindDerived && !locked[in] {
+			candidates = append(candidates, in)
+		}
+	}
+	if len(candidates) <= keep {
+		return
+	}
+	slices.SortStableFunc(candidates, func(a, b *Incompatibility) int {
+		// Most active first
+		switch {
+		case s.search.activity[a] > s.search.activity[b]:
+			return -1
+		case s.search.activity[a] < s.search.activity[b]:
+			return 1
+		}
+		return 0
+	})
+
+	forgotten := map[*Incompatibility]bool{}
+	for _, in := range candidates[keep:] {
+		forgotten[in] = true
+		delete(s.search.activity, in)
+	}
+	s.incompatibilities = slices.DeleteFunc(s.incompatibilities, func(in *Incompatibility) bool {
+		return forgotten[in]
+	})
+	s.statistics.ForgottenIncompatibilities += len(forgotten)
+}
+
+// restart backtracks to decision level 0, keeping everything derived there
+func (s *solver) restart() {
+	for i, a := range s.partialSolution.assignments {
+		if _, ok := a.(decision); ok {
+			s.partialSolution = s.partialSolution.prefix(i)
+			break
+		}
+	}
+	s.statistics.Restarts++
+}
+
+// savedVersion returns the version decided for pkg before the latest restart, if it is among the compatible versions
+func (s *solver) savedVersion(pkg string, compatibleVersions []semver.Version) (semver.Version, bool) {
+	if !s.searchPolicyEnabled() {
+		return semver.Version{}, false
+	}
+	saved, ok := s.search.savedVersions[pkg]
+	if !ok {
+		return semver.Version{}, false
+	}
+	if !slices.ContainsFunc(compatibleVersions, func(v semver.Version) bool {
+		return v.Compare(saved) == 0
+	}) {
+		return semver.Version{}, false
+	}
+	return saved, true
+}
@@ -0,0 +1,48 @@
+package pubgrub_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub"
+	"github.com/mircearoata/pubgrub-go/pubgrub/synthetic"
+	"github.com/pkg/errors"
+)
+
+var aggressiveSearchPolicy = pubgrub.SearchPolicy{
+	ReduceInterval:  2,
+	KeepLearned:     1,
+	RestartInterval: 2,
+	RestartGrowth:   1.2,
+}
+
+func TestSolver_SearchPolicy(t *testing.T) {
+	t.Parallel()
+
+	source := synthetic.Pigeonhole(4, 4)
+	solution, err := pubgrub.SolveWithOptions(source, synthetic.RootPackage, pubgrub.Options{SearchPolicy: aggressiveSearchPolicy})
+	testza.AssertNoError(t, err)
+	for p := 1; p <= 4; p++ {
+		hole := solution.Versions[fmt.Sprintf("pigeon%d", p)].Major()
+		testza.AssertEqual(t, p, solution.Versions[fmt.Sprintf("hole%d", hole)].Major())
+	}
+
+	source = synthetic.Pigeonhole(5, 4)
+	_, err = pubgrub.SolveWithOptions(source, synthetic.RootPackage, pubgrub.Options{})
+	var solvingError pubgrub.SolvingError
+	testza.AssertTrue(t, errors.As(err, &solvingError))
+
+	var limitErr pubgrub.LimitExceededError
+	_, err = pubgrub.SolveWithOptions(source, synthetic.RootPackage, pubgrub.Options{
+		SearchPolicy: aggressiveSearchPolicy,
+		// Stop before the end, to look at what the policy did so far
+		Limits: pubgrub.Limits{MaxConflicts: 30},
+	})
+	testza.AssertTrue(t, errors.As(err, &limitErr))
+	testza.AssertNotEqual(t, 0, limitErr.Statistics.Restarts)
+	testza.AssertNotEqual(t, 0, limitErr.Statistics.ForgottenIncompatibilities)
+
+	_, err = pubgrub.SolveWithOptions(source, synthetic.RootPackage, pubgrub.Options{SearchPolicy: aggressiveSearchPolicy})
+	testza.AssertTrue(t, errors.As(err, &solvingError))
+}
@@ -125,9 +125,10 @@ func (v Constraint) canonical() Constraint {
 				return result
 			}
 			// If the versions are equal, order the lower bound before the upper bound for the merge to continue,
-			// but only if the one of the bounds is inclusive
-			if a.isUpper != b.isUpper && (a.isInclusive || b.isInclusive) {
-				if a.isUpper {
+			// but only if the one of the bounds is inclusive.
+			// Otherwise, the version is in neither range, so the upper bound closes its range first.
+			if a.isUpper != b.isUpper {
+				if a.isUpper == (a.isInclusive || b.isInclusive) {
 					return 1
 				}
 				return -1
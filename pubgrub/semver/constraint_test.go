@@ -73,3 +73,72 @@ func TestConstraint_Intersect(t *testing.T) {
 		})
 	}
 }
+
+func TestConstraint_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"*", "*"},
+		{"", ""},
+		{"1.2.3", "1.2.3"},
+		{">=1.0.0 <2.0.0", "^1.0.0"},
+		{">=0.1.0 <0.2.0", "^0.1.0"},
+		{">=1.2.0 <1.3.0", "~1.2.0"},
+		{">=1.0.0 <1.1.0", "~1.0.0"},
+		{">=0.0.1 <0.1.0", "~0.0.1"},
+		{">=1.2.3 <1.3.0", "~1.2.3"},
+		// ~0.0.1 only allows patch versions, so a range up to the next major version has no shorthand
+		{">=0.0.1 <1.0.0", ">=0.0.1 <1.0.0"},
+		{">=0.0.0 <1.0.0", ">=0.0.0 <1.0.0"},
+		// The bounds being equal is not an exact version when the upper bound is exclusive
+		{">=1.0.0 <1.0.0", ">=1.0.0 <1.0.0"},
+		{">1.0.0 <=2.0.0-alpha", ">1.0.0 <=2.0.0-alpha"},
+		{"<1.0.0 || >1.0.0", "<1.0.0 || >1.0.0"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.constraint, func(t *testing.T) {
+			t.Parallel()
+			c, err := NewConstraint(test.constraint)
+			testza.AssertNoError(t, err, "NewConstraint(%s)", test.constraint)
+			testza.AssertEqual(t, test.expected, c.String(), "NewConstraint(%s).String()", test.constraint)
+
+			// The string is parsed back to the same constraint
+			parsed, err := NewConstraint(c.String())
+			testza.AssertNoError(t, err, "NewConstraint(%s)", c.String())
+			testza.AssertTrue(t, c.Equal(parsed), "NewConstraint(%s).Equal(%s)", c.String(), test.constraint)
+		})
+	}
+}
+
+func TestConstraint_Union(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		c1       string
+		c2       string
+		expected string
+	}{
+		{"^1.0.0", "^2.0.0", ">=1.0.0 <3.0.0"},
+		{"<1.0.0", ">=1.0.0", "*"},
+		{"<=1.0.0", ">1.0.0", "*"},
+		{"<1.0.0", ">1.0.0", "<1.0.0 || >1.0.0"},
+		{">1.1.0", "<1.1.0 || >1.1.0", "<1.1.0 || >1.1.0"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.c1+" union "+test.c2, func(t *testing.T) {
+			t.Parallel()
+			c1, err := NewConstraint(test.c1)
+			testza.AssertNoError(t, err, "NewConstraint(%s)", test.c1)
+			c2, err := NewConstraint(test.c2)
+			testza.AssertNoError(t, err, "NewConstraint(%s)", test.c2)
+			testza.AssertEqual(t, test.expected, c1.Union(c2).String(), "Union(%s, %s)", test.c1, test.c2)
+		})
+	}
+}
@@ -124,6 +124,23 @@ func (v Version) bumpMajor() Version {
 	}
 }
 
+func (v Version) Major() int {
+	return v.major
+}
+
+func (v Version) Minor() int {
+	return v.minor
+}
+
+func (v Version) Patch() int {
+	return v.patch
+}
+
+// Prerelease returns the dot separated identifiers of the pre-release part of the version
+func (v Version) Prerelease() []string {
+	return slices.Clone(v.pre)
+}
+
 func (v Version) IsPrerelease() bool {
 	return len(v.pre) != 0
 }
@@ -291,7 +291,7 @@ func (v versionRange) String() string {
 	}
 	if v.upperBound != nil && v.lowerBound != nil && v.lowerInclusive {
 		// Shorthand for exact version
-		if v.upperBound.Compare(*v.lowerBound) == 0 {
+		if v.upperBound.Compare(*v.lowerBound) == 0 && v.upperInclusive {
 			return v.lowerBound.String()
 		}
 
@@ -310,15 +310,8 @@ func (v versionRange) String() string {
 				return "^" + v.lowerBound.String()
 			}
 
-			// Shorthand for tilde version
-			var nextTildeVersion Version
-			switch {
-			case v.lowerBound.minor != 0:
-				nextTildeVersion = v.lowerBound.bumpMinor()
-			default:
-				nextTildeVersion = v.lowerBound.bumpMajor()
-			}
-			if v.upperBound.Compare(nextTildeVersion) == 0 {
+			// Shorthand for tilde version, ~1.2.3 being >=1.2.3 <1.3.0 whatever the major and minor versions are
+			if v.upperBound.Compare(v.lowerBound.bumpMinor()) == 0 {
 				return "~" + v.lowerBound.String()
 			}
 		}
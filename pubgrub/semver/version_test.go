@@ -113,3 +113,14 @@ func TestVersion_Compare(t *testing.T) {
 		})
 	}
 }
+
+func TestVersion_Components(t *testing.T) {
+	t.Parallel()
+
+	v, err := NewVersion("1.2.3-alpha.1+build")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, 1, v.Major())
+	testza.AssertEqual(t, 2, v.Minor())
+	testza.AssertEqual(t, 3, v.Patch())
+	testza.AssertEqual(t, []string{"alpha", "1"}, v.Prerelease())
+}
@@ -0,0 +1,177 @@
+package pubgrub
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// ErrNoSource is returned when solving a variation of the problem of a Solution that was not returned by the solver
+var ErrNoSource = errors.New("the solution was not returned by the solver, so it has no source")
+
+// Solution is the result of a successful solve
+type Solution struct {
+	Root string
+	// Versions maps every selected package, except the root, to its version
+	Versions map[string]semver.Version
+	// Edges are the dependencies between the selected packages, including those of the root.
+	// Optional dependencies are included when the package depended on is selected.
+	Edges []DependencyEdge
+	// DroppedRecommendations are the weak dependencies that could not be selected together with everything else
+	DroppedRecommendations []DroppedRecommendation
+	// Statistics describes the work done by the solve that found the solution
+	Statistics Statistics
+
+	// source and options are used to solve variations of the same problem
+	source  Source
+	options Options
+}
+
+// solvedSource returns the source the solution was found with
+func (s Solution) solvedSource() (Source, error) {
+	if s.source == nil {
+		return nil, ErrNoSource
+	}
+	return s.source, nil
+}
+
+// DependencyKind is the kind of dependency declared by a PackageVersion
+type DependencyKind int
+
+const (
+	DependencyKindRequired DependencyKind = iota
+	DependencyKindOptional
+	DependencyKindWeak
+)
+
+func (k DependencyKind) String() string {
+	switch k {
+	case DependencyKindRequired:
+		return "required"
+	case DependencyKindOptional:
+		return "optional"
+	case DependencyKindWeak:
+		return "weak"
+	}
+	return "unknown"
+}
+
+// DependencyEdge is a dependency of a selected package on another selected package
+type DependencyEdge struct {
+	Dependant  string
+	Dependency string
+	Constraint semver.Constraint
+	Kind       DependencyKind
+}
+
+// DroppedRecommendation is a weak dependency that was skipped because it conflicted with the rest of the solution
+type DroppedRecommendation struct {
+	Dependant  string
+	Dependency string
+	Constraint semver.Constraint
+	// Reason is the conflict that would have occurred had the weak dependency been kept
+	Reason SolvingError
+}
+
+func (s *solver) solution(dropped []DroppedRecommendation) Solution {
+	return Solution{
+		Root:                   s.rootPkg,
+		Versions:               s.versions(),
+		Edges:                  s.dependencyEdges(),
+		DroppedRecommendations: dropped,
+		Statistics:             s.statistics,
+		source:                 s.source,
+		options:                s.options,
+	}
+}
+
+// dependencyEdges returns the dependencies between the decided packages,
+// leaving out the virtual packages made up by the solver
+func (s *solver) dependencyEdges() []DependencyEdge {
+	versions := s.versions()
+	decisions := s.partialSolution.decisionsMap()
+	selected := func(pkg string) bool {
+		_, ok := versions[pkg]
+		return ok || pkg == s.rootPkg
+	}
+
+	var edges []DependencyEdge
+	for _, a := range s.partialSolution.assignments {
+		dec, ok := a.(decision)
+		if !ok || !selected(dec.pkg) {
+			continue
+		}
+		data := s.chosenVersions[dec.pkg]
+		addEdges := func(deps map[string]semver.Constraint, kind DependencyKind) {
+			for dep, constraint := range deps {
+				if kind == DependencyKindWeak && s.droppedWeakDependencies[weakDependency{dependant: dec.pkg, dependency: dep}] {
+					continue
+				}
+				target := s.selectedPackage(dep, decisions)
+				if !selected(target) {
+					continue
+				}
+				edges = append(edges, DependencyEdge{
+					Dependant:  dec.pkg,
+					Dependency: target,
+					Constraint: constraint,
+					Kind:       kind,
+				})
+			}
+		}
+		addEdges(data.Dependencies, DependencyKindRequired)
+		addEdges(data.OptionalDependencies, DependencyKindOptional)
+		addEdges(data.WeakDependencies, DependencyKindWeak)
+		if dec.pkg == s.rootPkg {
+			addEdges(s.redirectToCompatibilitySlots(s.options.Requirements), DependencyKindRequired)
+		}
+	}
+
+	slices.SortFunc(edges, func(a, b DependencyEdge) int {
+		if c := cmp.Compare(a.Dependant, b.Dependant); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Dependency, b.Dependency); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Kind, b.Kind); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Constraint.String(), b.Constraint.String())
+	})
+
+	// A package can depend on another more than once, such as the root through a requirement,
+	// so each pair becomes a single edge with the strongest kind and the intersected constraint
+	merged := edges[:0]
+	for _, e := range edges {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if last.Dependant == e.Dependant && last.Dependency == e.Dependency {
+				last.Constraint = last.Constraint.Intersect(e.Constraint)
+				last.Kind = min(last.Kind, e.Kind)
+				continue
+			}
+		}
+		merged = append(merged, e)
+	}
+	return merged
+}
+
+// selectedPackage returns the package under which the package pkg is selected,
+// which is the slot of its decided version if pkg is a slotted package
+func (s *solver) selectedPackage(pkg string, decisions map[string]semver.Version) string {
+	slots, ok := s.options.CompatibilitySlots[pkg]
+	if !ok {
+		return pkg
+	}
+	v, ok := decisions[pkg]
+	if !ok {
+		return pkg
+	}
+	if slot, ok := findCompatibilitySlot(slots, v); ok {
+		return CompatibilitySlotPackage(pkg, slot.Name)
+	}
+	return pkg
+}
@@ -0,0 +1,255 @@
+package pubgrub
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+// ChangeKind is how a package differs between two solutions
+type ChangeKind int
+
+const (
+	ChangeKindAdded ChangeKind = iota
+	ChangeKindRemoved
+	ChangeKindUpgraded
+	ChangeKindDowngraded
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeKindAdded:
+		return "added"
+	case ChangeKindRemoved:
+		return "removed"
+	case ChangeKindUpgraded:
+		return "upgraded"
+	case ChangeKindDowngraded:
+		return "downgraded"
+	}
+	return "unknown"
+}
+
+func (k ChangeKind) noun() string {
+	switch k {
+	case ChangeKindAdded:
+		return "addition"
+	case ChangeKindRemoved:
+		return "removal"
+	case ChangeKindUpgraded:
+		return "upgrade"
+	case ChangeKindDowngraded:
+		return "downgrade"
+	}
+	return "change"
+}
+
+// ChangeLevel is the most significant version component that changed between two versions
+type ChangeLevel int
+
+const (
+	ChangeLevelNone ChangeLevel = iota
+	ChangeLevelMajor
+	ChangeLevelMinor
+	ChangeLevelPatch
+	ChangeLevelPrerelease
+)
+
+func (l ChangeLevel) String() string {
+	switch l {
+	case ChangeLevelNone:
+		return "none"
+	case ChangeLevelMajor:
+		return "major"
+	case ChangeLevelMinor:
+		return "minor"
+	case ChangeLevelPatch:
+		return "patch"
+	case ChangeLevelPrerelease:
+		return "prerelease"
+	}
+	return "unknown"
+}
+
+// PackageChange is a package whose version differs between two solutions
+type PackageChange struct {
+	Package string
+	Kind    ChangeKind
+	// Level is only set for upgrades and downgrades
+	Level ChangeLevel
+	// Old is nil for added packages
+	Old *semver.Version
+	// New is nil for removed packages
+	New *semver.Version
+}
+
+// SolutionDiff lists the changes between two solutions, sorted by package
+type SolutionDiff struct {
+	Changes []PackageChange
+}
+
+// DiffSolutions compares the selected versions of two solutions
+func DiffSolutions(oldSolution, newSolution Solution) SolutionDiff {
+	return DiffVersions(oldSolution.Versions, newSolution.Versions)
+}
+
+// DiffVersions compares two maps of selected versions
+func DiffVersions(oldVersions, newVersions map[string]semver.Version) SolutionDiff {
+	var changes []PackageChange
+	for pkg, oldVersion := range oldVersions {
+		oldVersion := oldVersion
+		newVersion, ok := newVersions[pkg]
+		if !ok {
+			changes = append(changes, PackageChange{
+				Package: pkg,
+				Kind:    ChangeKindRemoved,
+				Old:     &oldVersion,
+			})
+			continue
+		}
+		cmp := newVersion.Compare(oldVersion)
+		if cmp == 0 {
+			continue
+		}
+		kind := ChangeKindUpgraded
+		if cmp < 0 {
+			kind = ChangeKindDowngraded
+		}
+		changes = append(changes, PackageChange{
+			Package: pkg,
+			Kind:    kind,
+			Level:   changeLevel(oldVersion, newVersion),
+			Old:     &oldVersion,
+			New:     &newVersion,
+		})
+	}
+	for pkg, newVersion := range newVersions {
+		newVersion := newVersion
+		if _, ok := oldVersions[pkg]; !ok {
+			changes = append(changes, PackageChange{
+				Package: pkg,
+				Kind:    ChangeKindAdded,
+				New:     &newVersion,
+			})
+		}
+	}
+	slices.SortFunc(changes, func(a, b PackageChange) int {
+		return strings.Compare(a.Package, b.Package)
+	})
+	return SolutionDiff{Changes: changes}
+}
+
+func changeLevel(a, b semver.Version) ChangeLevel {
+	switch {
+	case a.Major() != b.Major():
+		return ChangeLevelMajor
+	case a.Minor() != b.Minor():
+		return ChangeLevelMinor
+	case a.Patch() != b.Patch():
+		return ChangeLevelPatch
+	case a.Compare(b) != 0:
+		return ChangeLevelPrerelease
+	}
+	return ChangeLevelNone
+}
+
+// IsEmpty returns whether the two solutions select the same versions
+func (d SolutionDiff) IsEmpty() bool {
+	return len(d.Changes) == 0
+}
+
+// Summary counts the changes by kind and level, e.g. "3 minor upgrades, 1 major downgrade"
+func (d SolutionDiff) Summary() string {
+	if d.IsEmpty() {
+		return "no changes"
+	}
+
+	type group struct {
+		kind  ChangeKind
+		level ChangeLevel
+	}
+	counts := map[group]int{}
+	for _, c := range d.Changes {
+		counts[group{c.Kind, c.Level}]++
+	}
+
+	var parts []string
+	for _, kind := range []ChangeKind{ChangeKindUpgraded, ChangeKindDowngraded} {
+		for _, level := range []ChangeLevel{ChangeLevelMajor, ChangeLevelMinor, ChangeLevelPatch, ChangeLevelPrerelease} {
+			if n := counts[group{kind, level}]; n > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s %s", n, level, plural(kind.noun(), n)))
+			}
+		}
+	}
+	for _, kind := range []ChangeKind{ChangeKindAdded, ChangeKindRemoved} {
+		if n := counts[group{kind, ChangeLevelNone}]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s %s", n, plural("package", n), kind))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func plural(noun string, n int) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
+
+// String renders the summary followed by one line per change
+func (d SolutionDiff) String() string {
+	lines := []string{d.Summary()}
+	for _, c := range d.Changes {
+		switch c.Kind {
+		case ChangeKindAdded:
+			lines = append(lines, fmt.Sprintf("+ %s %s", c.Package, c.New))
+		case ChangeKindRemoved:
+			lines = append(lines, fmt.Sprintf("- %s %s", c.Package, c.Old))
+		case ChangeKindUpgraded, ChangeKindDowngraded:
+			lines = append(lines, fmt.Sprintf("~ %s %s -> %s (%s %s)", c.Package, c.Old, c.New, c.Level, c.Kind.noun()))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+type packageChangeJSON struct {
+	Package string `json:"package"`
+	Change  string `json:"change"`
+	Level   string `json:"level,omitempty"`
+	Old     string `json:"old,omitempty"`
+	New     string `json:"new,omitempty"`
+}
+
+func (c PackageChange) MarshalJSON() ([]byte, error) {
+	result := packageChangeJSON{
+		Package: c.Package,
+		Change:  c.Kind.String(),
+	}
+	if c.Level != ChangeLevelNone {
+		result.Level = c.Level.String()
+	}
+	if c.Old != nil {
+		result.Old = c.Old.String()
+	}
+	if c.New != nil {
+		result.New = c.New.String()
+	}
+	return json.Marshal(result) //nolint:wrapcheck
+}
+
+func (d SolutionDiff) MarshalJSON() ([]byte, error) {
+	changes := d.Changes
+	if changes == nil {
+		changes = []PackageChange{}
+	}
+	return json.Marshal(struct { //nolint:wrapcheck
+		Summary string          `json:"summary"`
+		Changes []PackageChange `json:"changes"`
+	}{
+		Summary: d.Summary(),
+		Changes: changes,
+	})
+}
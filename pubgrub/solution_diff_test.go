@@ -0,0 +1,71 @@
+package pubgrub
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+func TestDiffSolutions(t *testing.T) {
+	t.Parallel()
+
+	oldSolution := Solution{
+		Versions: map[string]semver.Version{
+			"foo":   newVersion("1.0.0"),
+			"bar":   newVersion("1.2.0"),
+			"baz":   newVersion("2.0.0"),
+			"qux":   newVersion("1.0.0"),
+			"quux":  newVersion("1.0.0-beta.1"),
+			"same":  newVersion("3.0.0"),
+			"other": newVersion("0.1.0"),
+		},
+	}
+	newSolution := Solution{
+		Versions: map[string]semver.Version{
+			"foo":  newVersion("1.1.0"),
+			"bar":  newVersion("1.3.0"),
+			"baz":  newVersion("1.9.0"),
+			"quux": newVersion("1.0.0-beta.2"),
+			"same": newVersion("3.0.0"),
+			"new":  newVersion("0.2.0"),
+		},
+	}
+
+	diff := DiffSolutions(oldSolution, newSolution)
+	testza.AssertEqual(t, "2 minor upgrades, 1 prerelease upgrade, 1 major downgrade, 1 package added, 2 packages removed", diff.Summary())
+	testza.AssertEqual(t, `2 minor upgrades, 1 prerelease upgrade, 1 major downgrade, 1 package added, 2 packages removed
+~ bar 1.2.0 -> 1.3.0 (minor upgrade)
+~ baz 2.0.0 -> 1.9.0 (major downgrade)
+~ foo 1.0.0 -> 1.1.0 (minor upgrade)
++ new 0.2.0
+- other 0.1.0
+~ quux 1.0.0-beta.1 -> 1.0.0-beta.2 (prerelease upgrade)
+- qux 1.0.0`, diff.String())
+
+	data, err := json.Marshal(diff)
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, `{"summary":"2 minor upgrades, 1 prerelease upgrade, 1 major downgrade, 1 package added, 2 packages removed","changes":[`+
+		`{"package":"bar","change":"upgraded","level":"minor","old":"1.2.0","new":"1.3.0"},`+
+		`{"package":"baz","change":"downgraded","level":"major","old":"2.0.0","new":"1.9.0"},`+
+		`{"package":"foo","change":"upgraded","level":"minor","old":"1.0.0","new":"1.1.0"},`+
+		`{"package":"new","change":"added","new":"0.2.0"},`+
+		`{"package":"other","change":"removed","old":"0.1.0"},`+
+		`{"package":"quux","change":"upgraded","level":"prerelease","old":"1.0.0-beta.1","new":"1.0.0-beta.2"},`+
+		`{"package":"qux","change":"removed","old":"1.0.0"}]}`, string(data))
+}
+
+func TestDiffSolutions_NoChanges(t *testing.T) {
+	t.Parallel()
+
+	solution := Solution{
+		Versions: map[string]semver.Version{
+			"foo": newVersion("1.0.0"),
+		},
+	}
+
+	diff := DiffSolutions(solution, solution)
+	testza.AssertTrue(t, diff.IsEmpty())
+	testza.AssertEqual(t, "no changes", diff.String())
+}
@@ -0,0 +1,149 @@
+package pubgrub
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CycleError is returned when the selected packages cannot be ordered because they depend on each other
+type CycleError struct {
+	// Cycle lists the packages of a cycle, starting and ending with the same package
+	Cycle []string
+}
+
+func (e CycleError) Error() string {
+	return fmt.Sprintf("dependency cycle: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// Dependencies returns the edges from pkg to the packages it depends on
+func (s Solution) Dependencies(pkg string) []DependencyEdge {
+	var result []DependencyEdge
+	for _, e := range s.Edges {
+		if e.Dependant == pkg {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Dependants returns the edges from the packages depending on pkg to pkg
+func (s Solution) Dependants(pkg string) []DependencyEdge {
+	var result []DependencyEdge
+	for _, e := range s.Edges {
+		if e.Dependency == pkg {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// IsDirect returns whether the root depends on pkg
+func (s Solution) IsDirect(pkg string) bool {
+	return slices.ContainsFunc(s.Edges, func(e DependencyEdge) bool {
+		return e.Dependant == s.Root && e.Dependency == pkg
+	})
+}
+
+// DirectDependencies returns the selected packages the root depends on, sorted alphabetically
+func (s Solution) DirectDependencies() []string {
+	var result []string
+	for pkg := range s.Versions {
+		if s.IsDirect(pkg) {
+			result = append(result, pkg)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// TransitiveDependencies returns the selected packages the root does not depend on directly, sorted alphabetically
+func (s Solution) TransitiveDependencies() []string {
+	var result []string
+	for pkg := range s.Versions {
+		if !s.IsDirect(pkg) {
+			result = append(result, pkg)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// InstallOrder returns the selected packages ordered so that each package comes after all of its dependencies.
+// Packages that are ready at the same time are ordered alphabetically.
+// If some packages depend on each other, a CycleError is returned.
+func (s Solution) InstallOrder() ([]string, error) {
+	remaining := map[string]int{}
+	for pkg := range s.Versions {
+		remaining[pkg] = 0
+	}
+	dependants := map[string][]string{}
+	for _, e := range s.Edges {
+		if _, ok := remaining[e.Dependant]; !ok || e.Dependant == e.Dependency {
+			continue
+		}
+		if _, ok := remaining[e.Dependency]; !ok {
+			continue
+		}
+		remaining[e.Dependant]++
+		dependants[e.Dependency] = append(dependants[e.Dependency], e.Dependant)
+	}
+
+	var ready []string
+	for pkg, count := range remaining {
+		if count == 0 {
+			ready = append(ready, pkg)
+		}
+	}
+
+	order := make([]string, 0, len(s.Versions))
+	for len(ready) > 0 {
+		slices.Sort(ready)
+		pkg := ready[0]
+		ready = ready[1:]
+		delete(remaining, pkg)
+		order = append(order, pkg)
+		for _, dependant := range dependants[pkg] {
+			remaining[dependant]--
+			if remaining[dependant] == 0 {
+				ready = append(ready, dependant)
+			}
+		}
+	}
+
+	if len(remaining) > 0 {
+		return nil, CycleError{Cycle: s.findCycle(remaining)}
+	}
+	return order, nil
+}
+
+// findCycle returns a cycle among the given packages, each of which is part of or depends on a cycle
+func (s Solution) findCycle(packages map[string]int) []string {
+	candidates := make([]string, 0, len(packages))
+	for pkg := range packages {
+		candidates = append(candidates, pkg)
+	}
+	slices.Sort(candidates)
+
+	// Following any dependency that is still remaining eventually revisits a package
+	path := []string{candidates[0]}
+	index := map[string]int{candidates[0]: 0}
+	for {
+		current := path[len(path)-1]
+		next := ""
+		for _, e := range s.Dependencies(current) {
+			if _, ok := packages[e.Dependency]; ok && e.Dependency != current {
+				next = e.Dependency
+				break
+			}
+		}
+		if next == "" {
+			return path
+		}
+		if i, ok := index[next]; ok {
+			return append(path[i:], next)
+		}
+		index[next] = len(path)
+		path = append(path, next)
+	}
+}
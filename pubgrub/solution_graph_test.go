@@ -0,0 +1,166 @@
+package pubgrub
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+func TestSolution_Graph(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"app": newConstraint("^1.0.0"),
+						"lib": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"app": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"util": newConstraint("^1.0.0"),
+					},
+					OptionalDependencies: map[string]semver.Constraint{
+						"lib":   newConstraint("^1.0.0"),
+						"extra": newConstraint("^1.0.0"),
+					},
+					WeakDependencies: map[string]semver.Constraint{
+						"docs": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"lib": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"util": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"util": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+			"docs": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{})
+	testza.AssertNoError(t, err)
+
+	expectedEdges := []DependencyEdge{
+		{Dependant: "$$root$$", Dependency: "app", Constraint: newConstraint("^1.0.0"), Kind: DependencyKindRequired},
+		{Dependant: "$$root$$", Dependency: "lib", Constraint: newConstraint("^1.0.0"), Kind: DependencyKindRequired},
+		{Dependant: "app", Dependency: "docs", Constraint: newConstraint("^1.0.0"), Kind: DependencyKindWeak},
+		{Dependant: "app", Dependency: "lib", Constraint: newConstraint("^1.0.0"), Kind: DependencyKindOptional},
+		{Dependant: "app", Dependency: "util", Constraint: newConstraint("^1.0.0"), Kind: DependencyKindRequired},
+		{Dependant: "lib", Dependency: "util", Constraint: newConstraint("^1.0.0"), Kind: DependencyKindRequired},
+	}
+	testza.AssertEqual(t, expectedEdges, solution.Edges)
+
+	testza.AssertEqual(t, []string{"app", "lib"}, solution.DirectDependencies())
+	testza.AssertEqual(t, []string{"docs", "util"}, solution.TransitiveDependencies())
+
+	order, err := solution.InstallOrder()
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, []string{"docs", "util", "lib", "app"}, order)
+}
+
+func TestSolution_InstallOrder_Cycle(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"a": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"a": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"b": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"b": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"c": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"c": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"a": newConstraint("^1.0.0"),
+					},
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{})
+	testza.AssertNoError(t, err)
+
+	order, err := solution.InstallOrder()
+	testza.AssertNil(t, order)
+	testza.AssertEqual(t, CycleError{Cycle: []string{"a", "b", "c", "a"}}, err)
+	testza.AssertEqual(t, "dependency cycle: a -> b -> c -> a", err.Error())
+}
+
+func TestSolution_InstallOrder_DependsOnRoot(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"plugin": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"plugin": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"$$root$$": newConstraint("^1.0.0"),
+						"util":     newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"util": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{})
+	testza.AssertNoError(t, err)
+
+	order, err := solution.InstallOrder()
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, []string{"util", "plugin"}, order)
+}
@@ -0,0 +1,140 @@
+package pubgrub
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// SolutionIterator finds successive distinct solutions of the same problem.
+// The solutions are searched without the weak dependencies.
+// After each solution, an incompatibility forbidding its decisions is added,
+// and the search continues from there, keeping every incompatibility learned so far.
+// The weak dependencies are then added to each solution without changing its versions.
+type SolutionIterator struct {
+	source  Source
+	rootPkg string
+	options Options
+	limit   int
+
+	sv       *Solver
+	s        *solver
+	found    int
+	seen     map[string]bool
+	solution Solution
+	err      error
+	done     bool
+}
+
+// NewSolutionIterator creates an iterator over at most limit solutions, or over all solutions if limit is 0
+func NewSolutionIterator(source Source, rootPkg string, options Options, limit int) *SolutionIterator {
+	return &SolutionIterator{
+		source:  source,
+		rootPkg: rootPkg,
+		options: options,
+		limit:   limit,
+		seen:    map[string]bool{},
+	}
+}
+
+// Next searches for the next solution.
+// It returns false when there are no more solutions, the limit is reached, or an error occurred.
+func (it *SolutionIterator) Next() bool {
+	if it.done || (it.limit > 0 && it.found >= it.limit) {
+		return false
+	}
+
+	for {
+		if it.s == nil {
+			it.sv = newStepwiseSolver(it.source, it.rootPkg, it.options, nil)
+			s, err := it.sv.solveWithoutWeakDependencies()
+			if err != nil {
+				// No solution at all is reported as an error
+				it.done = true
+				it.err = err
+				return false
+			}
+			it.s = s
+		} else {
+			err := it.s.nextSolution()
+			if err != nil {
+				// Running out of solutions is not an error
+				it.done = true
+				var solvingError SolvingError
+				if !errors.As(err, &solvingError) {
+					it.err = err
+				}
+				return false
+			}
+		}
+
+		// Different decisions of virtual packages can lead to the same versions
+		key := solutionKey(it.s.versions())
+		if it.seen[key] {
+			continue
+		}
+		it.seen[key] = true
+
+		solution, err := it.sv.solveWithWeakDependencies(it.s)
+		if err != nil {
+			it.done = true
+			it.err = err
+			return false
+		}
+
+		it.solution = solution
+		it.found++
+		return true
+	}
+}
+
+// Solution returns the solution found by the last call to Next
+func (it *SolutionIterator) Solution() Solution {
+	return it.solution
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *SolutionIterator) Err() error {
+	return it.err
+}
+
+// nextSolution forbids the current decisions and continues the search for another solution
+func (s *solver) nextSolution() error {
+	blocking := s.decisionsIncompatibility(IncompatibilityKindBlockedSolution)
+	s.addIncompatibility(blocking)
+
+	_, next, err := s.backjump(blocking)
+	if err != nil {
+		return err
+	}
+	return s.run(next)
+}
+
+// decisionsIncompatibility returns an incompatibility forbidding the current decisions, except that of the root
+func (s *solver) decisionsIncompatibility(kind IncompatibilityKind) *Incompatibility {
+	in := &Incompatibility{
+		terms: map[string]Term{},
+		kind:  kind,
+	}
+	for _, a := range s.partialSolution.assignments {
+		if dec, ok := a.(decision); ok && dec.pkg != s.rootPkg {
+			in.terms[dec.pkg] = Term{
+				pkg:               dec.pkg,
+				versionConstraint: semver.SingleVersionConstraint(dec.version),
+				positive:          true,
+			}
+		}
+	}
+	return in
+}
+
+func solutionKey(versions map[string]semver.Version) string {
+	entries := make([]string, 0, len(versions))
+	for pkg, v := range versions {
+		entries = append(entries, pkg+"@"+v.String())
+	}
+	slices.Sort(entries)
+	return strings.Join(entries, ",")
+}
@@ -0,0 +1,139 @@
+package pubgrub
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+func TestSolutionIterator(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("*"),
+						"bar": newConstraint("*"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^2.0.0"),
+					},
+				},
+			},
+		},
+	}
+
+	it := NewSolutionIterator(source, "$$root$$", Options{}, 0)
+	var solutions []map[string]semver.Version
+	for it.Next() {
+		solutions = append(solutions, it.Solution().Versions)
+	}
+	testza.AssertNoError(t, it.Err())
+
+	expected := []map[string]semver.Version{
+		{"foo": newVersion("2.0.0"), "bar": newVersion("2.0.0")},
+		{"foo": newVersion("2.0.0"), "bar": newVersion("1.0.0")},
+		{"foo": newVersion("1.0.0"), "bar": newVersion("1.0.0")},
+	}
+	testza.AssertEqual(t, expected, solutions)
+
+	limited := NewSolutionIterator(source, "$$root$$", Options{}, 2)
+	count := 0
+	for limited.Next() {
+		count++
+	}
+	testza.AssertNoError(t, limited.Err())
+	testza.AssertEqual(t, 2, count)
+}
+
+func TestSolutionIterator_NoSolution(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^2.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+		},
+	}
+
+	it := NewSolutionIterator(source, "$$root$$", Options{}, 0)
+	testza.AssertFalse(t, it.Next())
+	testza.AssertEqual(t, "So, because installing foo \"^2.0.0\" and foo \"^2.0.0\" is forbidden, version solving failed.", it.Err().Error())
+}
+
+func TestSolutionIterator_WeakDependencies(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("*"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("1.1.0"),
+					WeakDependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^2.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+		},
+	}
+
+	it := NewSolutionIterator(source, "$$root$$", Options{}, 0)
+	var solutions []Solution
+	for it.Next() {
+		solutions = append(solutions, it.Solution())
+	}
+	testza.AssertNoError(t, it.Err())
+
+	testza.AssertLen(t, solutions, 2)
+	testza.AssertEqual(t, map[string]semver.Version{"foo": newVersion("1.1.0")}, solutions[0].Versions)
+	testza.AssertLen(t, solutions[0].DroppedRecommendations, 1)
+	testza.AssertEqual(t, map[string]semver.Version{"foo": newVersion("1.0.0")}, solutions[1].Versions)
+	testza.AssertLen(t, solutions[1].DroppedRecommendations, 0)
+}
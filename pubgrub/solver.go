@@ -14,13 +14,58 @@ type solver struct {
 	incompatibilities []*Incompatibility
 	partialSolution   partialSolution
 
-	source Source
+	source  Source
+	options Options
+
+	versionGroups  map[string]VersionGroup
+	versionGroupOf map[string]VersionGroup
+
+	compatibilitySlotPackages map[string]compatibilitySlotRef
+
+	droppedWeakDependencies map[weakDependency]bool
+	// ignoreWeakDependencies is set while searching for the versions without the weak dependencies
+	ignoreWeakDependencies bool
+
+	// prune is called after each unit propagation, and may return an incompatibility satisfied by the current decisions
+	// to steer the search away from them
+	prune func() (*Incompatibility, error)
+
+	statistics Statistics
+	search     searchState
+
+	// The state of the step-wise search
+	phase                 stepPhase
+	propagation           propagation
+	conflict              *Incompatibility
+	conflictInPropagation bool
+	err                   error
+
+	// chosenVersions holds the data of the version chosen by the latest decision of each package
+	chosenVersions map[string]PackageVersion
 }
 
 func Solve(source Source, rootPkg string) (map[string]semver.Version, error) {
-	s := solver{
-		source:  source,
-		rootPkg: rootPkg,
+	solution, err := SolveWithOptions(source, rootPkg, Options{})
+	if err != nil {
+		return nil, err
+	}
+	return solution.Versions, nil
+}
+
+func SolveWithOptions(source Source, rootPkg string, options Options) (Solution, error) {
+	return NewSolver(source, rootPkg, options).Solve()
+}
+
+func newSolver(source Source, rootPkg string, options Options) (*solver, error) {
+	if err := validateCompatibilitySlots(options.CompatibilitySlots); err != nil {
+		return nil, err
+	}
+
+	s := &solver{
+		source:         source,
+		rootPkg:        rootPkg,
+		options:        options,
+		chosenVersions: map[string]PackageVersion{},
 		incompatibilities: []*Incompatibility{
 			{
 				terms: map[string]Term{
@@ -30,53 +75,42 @@ func Solve(source Source, rootPkg string) (map[string]semver.Version, error) {
 						positive:          false,
 					},
 				},
+				kind: IncompatibilityKindRoot,
 			},
 		},
 	}
 
-	next := rootPkg
-
-	for {
-		err := s.unitPropagation(next)
-		if err != nil {
-			return nil, err
-		}
-
-		// Prefetch all positive undecided packages
-		undecided := s.partialSolution.allPositiveUndecided()
-		go func() {
-			for _, pkg := range undecided {
-				go func(pkg string) {
-					_, _ = s.source.GetPackageVersions(pkg)
-				}(pkg)
-			}
-		}()
+	s.initSearchState()
+	s.indexVersionGroups()
+	s.indexCompatibilitySlots()
+	s.addRequirementIncompatibilities()
+	s.addConstraintIncompatibilities()
 
-		var done bool
-		next, done, err = s.decision()
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to make decision")
-		}
-		if done {
-			break
-		}
-	}
+	return s, nil
+}
 
+// versions returns the decided versions of all real packages, except the root
+func (s *solver) versions() map[string]semver.Version {
 	result := s.partialSolution.decisionsMap()
-	delete(result, rootPkg)
-	return result, nil
+	delete(result, s.rootPkg)
+	for group := range s.versionGroups {
+		delete(result, group)
+	}
+	s.removeSlottedSelectors(result)
+	return result
 }
 
-func (s *solver) unitPropagation(inPkg string) error {
-	changed := []string{inPkg}
-	var contradictedIncompatibilities []*Incompatibility
-	for len(changed) > 0 {
-		pkg := changed[0]
-		changed = changed[1:]
+// propagate derives the terms forced by the incompatibilities involving the changed packages, until there are none left.
+// It returns the first incompatibility found satisfied, which is a conflict to resolve before propagating further.
+func (s *solver) propagate() *Incompatibility {
+	p := &s.propagation
+	for len(p.changed) > 0 {
+		pkg := p.changed[0]
+		p.changed = p.changed[1:]
 
 		for i := len(s.incompatibilities) - 1; i >= 0; i-- {
 			currentIncompatibility := s.incompatibilities[i]
-			if slices.Contains(contradictedIncompatibilities, currentIncompatibility) {
+			if slices.Contains(p.contradicted, currentIncompatibility) {
 				continue
 			}
 			hasPkg := false
@@ -92,34 +126,45 @@ func (s *solver) unitPropagation(inPkg string) error {
 
 			rel, t := currentIncompatibility.relation(&s.partialSolution)
 			if rel == setRelationSatisfied {
-				newIncompatibility, err := s.conflictResolution(currentIncompatibility)
-				if err != nil {
-					return err
-				}
-				newRel, newT := newIncompatibility.relation(&s.partialSolution)
-				if newRel != setRelationAlmostSatisfied {
-					return errors.New("new incompatibility is not almost satisfied, this should never happen")
-				}
-				s.partialSolution.add(newT.Negate(), newIncompatibility)
-				changed = []string{newT.pkg}
-				contradictedIncompatibilities = append(contradictedIncompatibilities, newIncompatibility)
-				break
+				return currentIncompatibility
 			} else if rel == setRelationAlmostSatisfied {
 				s.partialSolution.add(t.Negate(), currentIncompatibility)
-				changed = append(changed, t.pkg)
+				p.changed = append(p.changed, t.pkg)
 			}
-			contradictedIncompatibilities = append(contradictedIncompatibilities, currentIncompatibility)
+			p.contradicted = append(p.contradicted, currentIncompatibility)
 		}
 	}
 	return nil
 }
 
+// backjump resolves the conflict caused by the satisfied incompatibility,
+// and derives the term the resulting incompatibility forces after backtracking.
+// It returns the resulting incompatibility and the package of the derived term.
+func (s *solver) backjump(conflict *Incompatibility) (*Incompatibility, string, error) {
+	s.statistics.Conflicts++
+	s.onConflict()
+	if err := s.checkLimits(); err != nil {
+		return nil, "", err
+	}
+	newIncompatibility, err := s.conflictResolution(conflict)
+	if err != nil {
+		return nil, "", err
+	}
+	newRel, newT := newIncompatibility.relation(&s.partialSolution)
+	if newRel != setRelationAlmostSatisfied {
+		return nil, "", errors.New("new incompatibility is not almost satisfied, this should never happen")
+	}
+	s.partialSolution.add(newT.Negate(), newIncompatibility)
+	return newIncompatibility, newT.pkg, nil
+}
+
 func (s *solver) conflictResolution(fromIncompatibility *Incompatibility) (*Incompatibility, error) {
 	incompatibilityChanged := false
 	for {
 		if s.isIncompatibilityTerminal(fromIncompatibility) {
 			return nil, SolvingError{fromIncompatibility}
 		}
+		s.bumpActivity(fromIncompatibility)
 
 		satisfierIdx := util.BinarySearchFunc(0, len(s.partialSolution.assignments), func(i int) bool {
 			prefix := s.partialSolution.prefix(i + 1)
@@ -137,7 +182,8 @@ func (s *solver) conflictResolution(fromIncompatibility *Incompatibility) (*Inco
 			return rel == setRelationSatisfied
 		})
 		var previousSatisfier assignment
-		previousSatisfierLevel := 1
+		// A conflict satisfied by level 0 alone, where only derivations are, is resolved until it is terminal
+		previousSatisfierLevel := min(1, satisfier.DecisionLevel())
 		if previousSatisfierIdx >= 0 {
 			previousSatisfier = s.partialSolution.assignments[previousSatisfierIdx]
 			previousSatisfierLevel = previousSatisfier.DecisionLevel()
@@ -145,7 +191,12 @@ func (s *solver) conflictResolution(fromIncompatibility *Incompatibility) (*Inco
 
 		if _, ok := satisfier.(decision); ok || previousSatisfierLevel != satisfier.DecisionLevel() {
 			if incompatibilityChanged {
-				s.addIncompatibility(fromIncompatibility)
+				if s.addIncompatibility(fromIncompatibility) {
+					s.statistics.LearnedIncompatibilities++
+					if err := s.checkLimits(); err != nil {
+						return nil, err
+					}
+				}
 			}
 
 			decLevel := 0
@@ -163,6 +214,7 @@ func (s *solver) conflictResolution(fromIncompatibility *Incompatibility) (*Inco
 		}
 
 		der := satisfier.(derivation)
+		s.bumpActivity(der.cause)
 
 		priorCause := fromIncompatibility.makePriorCause(der.cause, satisfier.Package())
 
@@ -172,6 +224,11 @@ func (s *solver) conflictResolution(fromIncompatibility *Incompatibility) (*Inco
 
 		fromIncompatibility = priorCause
 		incompatibilityChanged = true
+
+		s.statistics.DerivationDepth = max(s.statistics.DerivationDepth, priorCause.depth)
+		if err := s.checkLimits(); err != nil {
+			return nil, err
+		}
 	}
 }
 
@@ -183,9 +240,40 @@ func (s *solver) decision() (string, bool, error) {
 
 	t := s.partialSolution.get(pkg)
 
-	versions, err := s.source.GetPackageVersions(t.pkg)
+	// The version of a group is decided once for all of its packages
+	if groupTerm := s.undecidedVersionGroupTerm(*t); groupTerm != nil {
+		decided, err := s.decide(*groupTerm)
+		if err != nil {
+			return pkg, false, err
+		}
+		if decided {
+			return groupTerm.pkg, false, nil
+		}
+		// No version of the group is compatible with this package,
+		// so deciding the package itself will lead to the conflict
+	}
+
+	decided, err := s.decide(*t)
+	if err != nil {
+		return pkg, false, err
+	}
+	if !decided {
+		s.addIncompatibility(&Incompatibility{
+			terms: map[string]Term{pkg: *t},
+			kind:  IncompatibilityKindNoVersions,
+		})
+	}
+	return pkg, false, nil
+}
+
+// decide picks a version of the package of the positive term t and adds its dependencies.
+// If no version satisfies the term, nothing is changed and false is returned.
+func (s *solver) decide(t Term) (bool, error) {
+	pkg := t.pkg
+
+	versions, err := s.getPackageVersions(pkg)
 	if err != nil {
-		return pkg, false, errors.Wrap(err, "failed to get package versions")
+		return false, errors.Wrap(err, "failed to get package versions")
 	}
 
 	availableVersions := make([]semver.Version, 0, len(versions))
@@ -206,18 +294,18 @@ func (s *solver) decision() (string, bool, error) {
 	}
 
 	if len(versions) == 0 || len(compatibleVersions) == 0 {
-		s.addIncompatibility(&Incompatibility{
-			terms: map[string]Term{pkg: *t},
-		})
-		return pkg, false, nil
+		return false, nil
 	}
 
-	chosenVersion := s.source.PickVersion(t.pkg, compatibleVersions)
+	chosenVersion, ok := s.savedVersion(pkg, compatibleVersions)
+	if !ok {
+		chosenVersion = s.pickVersion(pkg, compatibleVersions)
+	}
 
 	if !slices.ContainsFunc(compatibleVersions, func(v semver.Version) bool {
 		return v.Compare(chosenVersion) == 0
 	}) {
-		return pkg, false, errors.New("chosen version not compatible")
+		return false, errors.New("chosen version not compatible")
 	}
 
 	var chosenVersionData *PackageVersion
@@ -258,6 +346,7 @@ func (s *solver) decision() (string, bool, error) {
 				},
 			},
 			dependant: pkg,
+			kind:      s.dependencyKind(pkg, dep),
 		})
 	}
 
@@ -296,27 +385,210 @@ func (s *solver) decision() (string, bool, error) {
 				},
 			},
 			dependant: pkg,
+			kind:      s.dependencyKind(pkg, dep),
 		})
 	}
 
+	// Add weak dependencies in a deterministic order (alphabetical)
+	weakDeps := make([]string, 0, len(chosenVersionData.WeakDependencies))
+	for dep := range chosenVersionData.WeakDependencies {
+		if !s.ignoreWeakDependencies && !s.droppedWeakDependencies[weakDependency{dependant: pkg, dependency: dep}] {
+			weakDeps = append(weakDeps, dep)
+		}
+	}
+	slices.Sort(weakDeps)
+	for _, dep := range weakDeps {
+		constraint := chosenVersionData.WeakDependencies[dep]
+		var versionsWithThisDependency []semver.Version
+		for _, v := range versions {
+			if vDep, ok := v.WeakDependencies[dep]; ok && constraint.Equal(vDep) {
+				versionsWithThisDependency = append(versionsWithThisDependency, v.Version)
+			}
+		}
+		slices.SortFunc(versionsWithThisDependency, func(a, b semver.Version) int {
+			return a.Compare(b)
+		})
+		s.addIncompatibility(&Incompatibility{
+			terms: map[string]Term{
+				pkg: {
+					pkg:               pkg,
+					versionConstraint: semver.NewConstraintFromVersionSubset(versionsWithThisDependency, availableVersions),
+					positive:          true,
+				},
+				dep: {
+					pkg:               dep,
+					versionConstraint: constraint,
+				},
+			},
+			dependant: pkg,
+			kind:      IncompatibilityKindWeakDependency,
+		})
+	}
+
+	s.statistics.Decisions++
+	if s.searchPolicyEnabled() {
+		s.search.savedVersions[pkg] = chosenVersion
+	}
+	s.chosenVersions[pkg] = *chosenVersionData
 	s.partialSolution.assignments = append(s.partialSolution.assignments, decision{
-		pkg:           t.pkg,
+		pkg:           pkg,
 		version:       chosenVersion,
 		decisionLevel: s.partialSolution.currentDecisionLevel() + 1,
 	})
 
-	return pkg, false, nil
+	return true, nil
+}
+
+// sourcePackage returns the package of the Source that holds the versions of pkg,
+// or false if pkg is a virtual package made up by the solver
+func (s *solver) sourcePackage(pkg string) (string, bool) {
+	if _, ok := s.versionGroups[pkg]; ok {
+		return "", false
+	}
+	if ref, ok := s.compatibilitySlotPackages[pkg]; ok {
+		return ref.pkg, true
+	}
+	return pkg, true
+}
+
+// getSourceVersions gets the versions of pkg from the Source, counting the call against the limits
+func (s *solver) getSourceVersions(pkg string) ([]PackageVersion, error) {
+	s.statistics.SourceCalls++
+	if err := s.checkLimits(); err != nil {
+		return nil, err
+	}
+	return s.source.GetPackageVersions(pkg) //nolint:wrapcheck
+}
+
+func (s *solver) getPackageVersions(pkg string) ([]PackageVersion, error) {
+	if group, ok := s.versionGroups[pkg]; ok {
+		return s.getVersionGroupVersions(group)
+	}
+
+	sourcePkg, _ := s.sourcePackage(pkg)
+	versions, err := s.getSourceVersions(sourcePkg)
+	if err != nil {
+		return nil, err
+	}
+
+	if pkg == s.rootPkg && s.options.RootDependencies != nil {
+		versions = withDependencies(versions, s.options.RootDependencies)
+	}
+
+	versions = s.withCompatibilitySlots(pkg, versions)
+
+	if group, ok := s.versionGroupOf[pkg]; ok {
+		return withVersionGroupDependency(versions, group), nil
+	}
+
+	return versions, nil
+}
+
+func (s *solver) pickVersion(pkg string, versions []semver.Version) semver.Version {
+	if group, ok := s.versionGroups[pkg]; ok {
+		// The source does not know about the group, so let it pick as if it were picking for a package of the group
+		return s.source.PickVersion(group.Packages[0], versions)
+	}
+	return s.source.PickVersion(pkg, versions)
+}
+
+func (s *solver) dependencyKind(pkg, dep string) IncompatibilityKind {
+	if _, ok := s.versionGroups[pkg]; ok {
+		return IncompatibilityKindVersionGroup
+	}
+	if _, ok := s.versionGroups[dep]; ok {
+		return IncompatibilityKindVersionGroup
+	}
+	return IncompatibilityKindDependency
+}
+
+// addRequirementIncompatibilities makes every version of the root depend on the requirements,
+// redirected to the compatibility slots like the dependencies of the root
+func (s *solver) addRequirementIncompatibilities() {
+	requirements := s.redirectToCompatibilitySlots(s.options.Requirements)
+	// Add requirements in a deterministic order (alphabetical)
+	pkgs := make([]string, 0, len(requirements))
+	for pkg := range requirements {
+		pkgs = append(pkgs, pkg)
+	}
+	slices.Sort(pkgs)
+	for _, pkg := range pkgs {
+		if pkg == s.rootPkg {
+			continue
+		}
+		s.addIncompatibility(&Incompatibility{
+			terms: map[string]Term{
+				s.rootPkg: {
+					pkg:               s.rootPkg,
+					versionConstraint: semver.AnyConstraint,
+					positive:          true,
+				},
+				pkg: {
+					pkg:               pkg,
+					versionConstraint: requirements[pkg],
+				},
+			},
+			dependant: s.rootPkg,
+			kind:      IncompatibilityKindDependency,
+		})
+	}
+}
+
+// addConstraintIncompatibilities forbids the versions outside each constraint, in the package and in each of its slots.
+// The resulting incompatibilities only contain a positive term, so they do not require the package to be selected.
+func (s *solver) addConstraintIncompatibilities() {
+	// Add constraints in a deterministic order (alphabetical)
+	pkgs := make([]string, 0, len(s.options.Constraints))
+	for pkg := range s.options.Constraints {
+		pkgs = append(pkgs, pkg)
+	}
+	slices.Sort(pkgs)
+	for _, pkg := range pkgs {
+		constraint := s.options.Constraints[pkg]
+		if pkg == s.rootPkg || constraint.IsAny() {
+			continue
+		}
+		s.addIncompatibility(&Incompatibility{
+			terms: map[string]Term{
+				pkg: {
+					pkg:               pkg,
+					versionConstraint: constraint.Inverse(),
+					positive:          true,
+				},
+			},
+			kind: IncompatibilityKindConstraint,
+		})
+		for _, slot := range s.options.CompatibilitySlots[pkg] {
+			forbidden := slot.Constraint.Intersect(constraint.Inverse())
+			if forbidden.IsEmpty() {
+				continue
+			}
+			slotPkg := CompatibilitySlotPackage(pkg, slot.Name)
+			s.addIncompatibility(&Incompatibility{
+				terms: map[string]Term{
+					slotPkg: {
+						pkg:               slotPkg,
+						versionConstraint: forbidden,
+						positive:          true,
+					},
+				},
+				kind: IncompatibilityKindConstraint,
+			})
+		}
+	}
 }
 
-func (s *solver) addIncompatibility(in *Incompatibility) {
+// addIncompatibility adds in unless an incompatibility with the same terms is already known, and reports whether it was added
+func (s *solver) addIncompatibility(in *Incompatibility) bool {
 	if slices.ContainsFunc(s.incompatibilities, func(i *Incompatibility) bool {
 		return maps.EqualFunc(i.terms, in.terms, func(a, b Term) bool {
 			return a.Equal(b)
 		})
 	}) {
-		return
+		return false
 	}
 	s.incompatibilities = append(s.incompatibilities, in)
+	return true
 }
 
 func (s *solver) isIncompatibilityTerminal(in *Incompatibility) bool {
@@ -105,6 +105,7 @@ func TestSolver_ConflictResolutionWithPartialSatisfier(t *testing.T) {
 		"target": newVersion("2.0.0"),
 	}
 	testza.AssertEqual(t, expected, result)
+	assertVerified(t, source, "$$root$$", result, Options{})
 }
 
 func TestSolver_LinearErrorReporting(t *testing.T) {
@@ -277,6 +278,7 @@ func TestSolver_OptionalDependencies_NoOptional(t *testing.T) {
 		"foo": newVersion("1.0.0"),
 	}
 	testza.AssertEqual(t, expected, result)
+	assertVerified(t, source, "$$root$$", result, Options{})
 }
 
 func TestSolver_OptionalDependencies_CompatibleVersion(t *testing.T) {
@@ -335,6 +337,7 @@ func TestSolver_OptionalDependencies_CompatibleVersion(t *testing.T) {
 		"baz": newVersion("1.0.0"),
 	}
 	testza.AssertEqual(t, expected, result)
+	assertVerified(t, source, "$$root$$", result, Options{})
 }
 
 func TestSolver_OptionalDependencies_Error(t *testing.T) {
@@ -383,3 +386,167 @@ func TestSolver_OptionalDependencies_Error(t *testing.T) {
 	expected := "Because every version of bar depends on baz \"^2.0.0\" and every version of foo depends on baz \"^1.0.0\", every version of bar forbids foo.\nSo, because installing bar \"^1.0.0\", version solving failed."
 	testza.AssertEqual(t, expected, err.Error())
 }
+
+func TestSolver_CompatibilitySlots_ConstraintsAndRequirements(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("*"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("1.1.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+				{
+					Version: newVersion("2.1.0"),
+				},
+			},
+		},
+	}
+	options := Options{
+		Requirements: map[string]semver.Constraint{
+			"foo": newConstraint("^2.0.0"),
+		},
+		Constraints: map[string]semver.Constraint{
+			"foo": newConstraint("<1.1.0 || >=2.0.0 <2.1.0"),
+		},
+		CompatibilitySlots: map[string][]CompatibilitySlot{
+			"foo": {
+				{Name: "1", Constraint: newConstraint("^1.0.0")},
+				{Name: "2", Constraint: newConstraint("^2.0.0")},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", options)
+	testza.AssertNoError(t, err)
+
+	expected := map[string]semver.Version{
+		"bar":   newVersion("1.0.0"),
+		"foo@1": newVersion("1.0.0"),
+		"foo@2": newVersion("2.0.0"),
+	}
+	testza.AssertEqual(t, expected, solution.Versions)
+	testza.AssertEqual(t, []DependencyEdge{
+		{Dependant: "$$root$$", Dependency: "bar", Constraint: newConstraint("*"), Kind: DependencyKindRequired},
+		{Dependant: "$$root$$", Dependency: "foo@2", Constraint: newConstraint("^2.0.0"), Kind: DependencyKindRequired},
+		{Dependant: "bar", Dependency: "foo@1", Constraint: newConstraint("^1.0.0"), Kind: DependencyKindRequired},
+	}, solution.Edges)
+}
+
+func TestSolver_CompatibilitySlots_Overlapping(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("*"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+		},
+	}
+
+	options := Options{
+		CompatibilitySlots: map[string][]CompatibilitySlot{
+			"foo": {
+				{Name: "1", Constraint: newConstraint("^1.0.0")},
+				{Name: "1-2", Constraint: newConstraint(">=1.5.0 <3.0.0")},
+			},
+		},
+	}
+	_, err := SolveWithOptions(source, "$$root$$", options)
+	testza.AssertNotNil(t, err)
+	var solvingErr SolvingError
+	testza.AssertFalse(t, errors.As(err, &solvingErr))
+	testza.AssertEqual(t, "compatibility slots 1 \"^1.0.0\" and 1-2 \">=1.5.0 <3.0.0\" of foo overlap", err.Error())
+
+	// Slots that only touch do not overlap
+	options.CompatibilitySlots["foo"][1].Constraint = newConstraint("^2.0.0")
+	solution, err := SolveWithOptions(source, "$$root$$", options)
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, newVersion("2.0.0"), solution.Versions["foo@1-2"])
+}
+
+func TestSolver_ErrorReporting_SharedPackages(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("*"),
+						"foo": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint(">=3.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("1.1.0"),
+					Dependencies: map[string]semver.Constraint{
+						"baz": newConstraint("1.0.0"),
+					},
+					OptionalDependencies: map[string]semver.Constraint{
+						"baz": newConstraint(">=3.0.0"),
+					},
+				},
+			},
+			"baz": {},
+		},
+	}
+
+	// Both causes of foo ">=1.1.0" being forbidden have a term of foo and of baz,
+	// so the cause listed first must not depend on which shared package is looked at first.
+	// The dependency, with the negative term, comes first.
+	expected := "Because installing every version of bar and every version of bar depends on foo \">=3.0.0\", installing foo \">=3.0.0\".\nBecause foo \">=1.1.0\" depends on baz \"1.0.0\" and foo \">=1.1.0\" depends on baz \">=3.0.0\", foo \">=1.1.0\" is forbidden.\nThus, version solving failed."
+	for i := 0; i < 20; i++ {
+		_, err := Solve(source, "$$root$$")
+		testza.AssertNotNil(t, err)
+		testza.AssertEqual(t, expected, err.Error())
+	}
+}
@@ -163,24 +163,16 @@ func writeErrorMessageRecursive(c *Incompatibility, writer SolvingErrorWriter) {
 		return
 	}
 
-	sharedPkg := ""
-	for _, t1 := range c1.Terms() {
-		for _, t2 := range c2.Terms() {
-			if t1.Dependency() == t2.Dependency() {
-				sharedPkg = t1.Dependency()
-				break
-			}
-		}
-	}
-	var first, second *Incompatibility
 	// The negative term is the package that is depended on
 	// Therefore we want the first incompatibility to be the one that has a negative term of the shared package
-	if !c1.get(sharedPkg).Positive() {
-		first = c1
-		second = c2
-	} else {
-		first = c2
-		second = c1
+	// The causes can share more than one package, so any negative shared term of c1 puts it first,
+	// which does not depend on the iteration order of the terms
+	first, second := c2, c1
+	for _, t := range c1.Terms() {
+		if c2.get(t.Dependency()) != nil && !t.Positive() {
+			first, second = c1, c2
+			break
+		}
 	}
 
 	writer.WriteLineTwoCauses(first, second, c)
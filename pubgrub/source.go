@@ -6,6 +6,11 @@ type PackageVersion struct {
 	Version              semver.Version
 	Dependencies         map[string]semver.Constraint
 	OptionalDependencies map[string]semver.Constraint
+	// WeakDependencies are installed if they can be selected together with everything else, and dropped otherwise.
+	// Each dropped weak dependency restarts the search, which keeps what it learned without relying on weak dependencies.
+	WeakDependencies map[string]semver.Constraint
+	// Metadata is arbitrary data about the version, not interpreted by the solver itself
+	Metadata map[string]any
 }
 
 type Source interface {
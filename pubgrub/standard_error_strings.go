@@ -33,19 +33,37 @@ var DefaultCauseStrings = StandardCauseStrings{
 type StandardIncompatibilityStrings struct {
 	ResolvingFailed string
 
-	DependsOn   string
-	Installing  string
-	Forbids     string
-	IsForbidden string
+	DependsOn     string
+	Recommends    string
+	Installing    string
+	Forbids       string
+	IsForbidden   string
+	IsConstrained string
+	IsSelected    string
+
+	InVersionGroup       string
+	VersionGroupRequires string
+
+	IsAssumed          string
+	IsAssumedForbidden string
 }
 
 var DefaultIncompatibilityStrings = StandardIncompatibilityStrings{
 	ResolvingFailed: "version solving failed",
 
-	DependsOn:   "%s depends on %s",
-	Installing:  "installing %s",
-	Forbids:     "%s forbids %s",
-	IsForbidden: "%s is forbidden",
+	DependsOn:     "%s depends on %s",
+	Recommends:    "%s recommends %s",
+	Installing:    "installing %s",
+	Forbids:       "%s forbids %s",
+	IsForbidden:   "%s is forbidden",
+	IsConstrained: "%s is constrained to \"%s\" by the project constraints",
+	IsSelected:    "%s is selected at \"%s\" regardless of recommendations",
+
+	InVersionGroup:       "%s is in version group %s",
+	VersionGroupRequires: "version group %s requires %s",
+
+	IsAssumed:          "%s is assumed",
+	IsAssumedForbidden: "%s is assumed to be forbidden",
 }
 
 type StandardTermStringer struct{}
@@ -88,6 +106,16 @@ func (w StandardIncompatibilityStringer) IncompatibilityString(c *Incompatibilit
 	terms := c.Terms()
 	if len(terms) == 1 {
 		t := terms[0]
+		if c.Kind() == IncompatibilityKindConstraint {
+			// The term holds the versions forbidden by the constraint, so the constraint itself is its inverse
+			return fmt.Sprintf(w.strings.IsConstrained, w.termStringer.Term(t, false), t.Constraint().Inverse())
+		}
+		if c.Kind() == IncompatibilityKindSelectedVersion {
+			return fmt.Sprintf(w.strings.IsSelected, w.termStringer.Term(t, false), t.Constraint().Inverse())
+		}
+		if c.Kind() == IncompatibilityKindAssumption {
+			return fmt.Sprintf(w.strings.IsAssumedForbidden, w.termStringer.Term(t, !t.Constraint().IsAny()))
+		}
 		if t.Positive() {
 			if t.Constraint().IsAny() {
 				return fmt.Sprintf(w.strings.IsForbidden, w.termStringer.Term(t, false))
@@ -96,6 +124,16 @@ func (w StandardIncompatibilityStringer) IncompatibilityString(c *Incompatibilit
 		}
 		panic("negative term in cause")
 	}
+	if c.Kind() == IncompatibilityKindVersionGroup {
+		return w.versionGroupString(c)
+	}
+	if c.Kind() == IncompatibilityKindAssumption {
+		for _, t := range terms {
+			if t.Dependency() != rootPkg {
+				return fmt.Sprintf(w.strings.IsAssumed, w.termStringer.Term(t.Negate(), !t.Constraint().IsAny()))
+			}
+		}
+	}
 	var pkg, dep Term
 	if terms[0].Positive() {
 		pkg = terms[0]
@@ -132,8 +170,28 @@ func (w StandardIncompatibilityStringer) IncompatibilityString(c *Incompatibilit
 	if dep.Constraint().IsEmpty() {
 		return fmt.Sprintf(w.strings.Forbids, w.termStringer.Term(pkg, true), w.termStringer.Term(dep, false))
 	}
+	dependsOn := w.strings.DependsOn
+	if c.Kind() == IncompatibilityKindWeakDependency {
+		dependsOn = w.strings.Recommends
+	}
 	if dep.Constraint().IsAny() {
-		return fmt.Sprintf(w.strings.DependsOn, w.termStringer.Term(pkg, true), w.termStringer.Term(dep, false))
+		return fmt.Sprintf(dependsOn, w.termStringer.Term(pkg, true), w.termStringer.Term(dep, false))
+	}
+	return fmt.Sprintf(dependsOn, w.termStringer.Term(pkg, true), w.termStringer.Term(dep, true))
+}
+
+func (w StandardIncompatibilityStringer) versionGroupString(c *Incompatibility) string {
+	var pkg, dep Term
+	for _, t := range c.Terms() {
+		if t.Dependency() == c.dependant {
+			pkg = t
+		} else {
+			dep = t
+		}
+	}
+	if dep.Positive() {
+		// The group optionally depends on the package, so the term has an inverse constraint
+		return fmt.Sprintf(w.strings.VersionGroupRequires, w.termStringer.Term(pkg, true), w.termStringer.Term(dep.Inverse(), true))
 	}
-	return fmt.Sprintf(w.strings.DependsOn, w.termStringer.Term(pkg, true), w.termStringer.Term(dep, true))
+	return fmt.Sprintf(w.strings.InVersionGroup, w.termStringer.Term(pkg, true), w.termStringer.Term(dep, true))
 }
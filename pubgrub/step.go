@@ -0,0 +1,209 @@
+package pubgrub
+
+import (
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// StepKind is what happened during a step of a Solver
+type StepKind int
+
+const (
+	// StepPropagation derived the terms forced by the incompatibilities, until none was left or a conflict was found
+	StepPropagation StepKind = iota
+	// StepConflictResolution learned an incompatibility from a conflict, and backtracked to where it forces a term
+	StepConflictResolution
+	// StepDecision selected a version of a package
+	StepDecision
+	// StepRestart backtracked to decision level 0 because of the SearchPolicy
+	StepRestart
+	// StepWeakDependenciesAdded found the versions without the weak dependencies,
+	// and started the search again with them, keeping those versions
+	StepWeakDependenciesAdded
+	// StepWeakDependencyDropped started the search again without a weak dependency involved in a conflict
+	StepWeakDependencyDropped
+	// StepSolved found a solution
+	StepSolved
+	// StepFailed ended the search without a solution
+	StepFailed
+)
+
+func (k StepKind) String() string {
+	switch k {
+	case StepPropagation:
+		return "propagation"
+	case StepConflictResolution:
+		return "conflict resolution"
+	case StepDecision:
+		return "decision"
+	case StepRestart:
+		return "restart"
+	case StepWeakDependenciesAdded:
+		return "weak dependencies added"
+	case StepWeakDependencyDropped:
+		return "weak dependency dropped"
+	case StepSolved:
+		return "solved"
+	case StepFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// Step describes what happened during a step of a Solver
+type Step struct {
+	Kind StepKind
+	// Package is the package decided, the package of the term derived after conflict resolution,
+	// or the weak dependency dropped
+	Package string
+	// Version is the version decided. It is nil if no version of the package could be decided,
+	// in which case an incompatibility forbidding its versions was added.
+	Version *semver.Version
+	// Incompatibility is the conflict found by propagation, or the incompatibility learned by conflict resolution
+	Incompatibility *Incompatibility
+	// Err is the error that ended the search, or the conflict that caused a weak dependency to be dropped
+	Err error
+}
+
+type stepPhase int
+
+const (
+	phasePropagate stepPhase = iota
+	phaseResolve
+	phaseDecide
+	phaseDone
+)
+
+// propagation holds the progress of unit propagation
+type propagation struct {
+	changed []string
+	// contradicted are the incompatibilities already known not to force anything more
+	contradicted []*Incompatibility
+}
+
+// run steps through the search, starting with unit propagation from the package next, until it ends
+func (s *solver) run(next string) error {
+	s.startPropagation(next)
+	for {
+		step := s.step()
+		switch step.Kind {
+		case StepSolved:
+			return nil
+		case StepFailed:
+			return step.Err
+		default:
+		}
+	}
+}
+
+func (s *solver) startPropagation(pkg string) {
+	s.phase = phasePropagate
+	s.propagation = propagation{changed: []string{pkg}}
+	s.err = nil
+}
+
+func (s *solver) fail(err error) Step {
+	s.phase = phaseDone
+	s.err = err
+	return Step{Kind: StepFailed, Err: err}
+}
+
+// step advances the search by a single propagation, conflict resolution or decision
+func (s *solver) step() Step {
+	switch s.phase {
+	case phasePropagate:
+		return s.propagationStep()
+	case phaseResolve:
+		return s.conflictResolutionStep()
+	case phaseDecide:
+		return s.decisionStep()
+	default:
+		if s.err != nil {
+			return Step{Kind: StepFailed, Err: s.err}
+		}
+		return Step{Kind: StepSolved}
+	}
+}
+
+func (s *solver) propagationStep() Step {
+	if conflict := s.propagate(); conflict != nil {
+		s.phase = phaseResolve
+		s.conflict = conflict
+		s.conflictInPropagation = true
+		return Step{Kind: StepPropagation, Incompatibility: conflict}
+	}
+
+	if s.prune != nil {
+		conflict, err := s.prune()
+		if err != nil {
+			return s.fail(err)
+		}
+		if conflict != nil {
+			s.addIncompatibility(conflict)
+			s.phase = phaseResolve
+			s.conflict = conflict
+			s.conflictInPropagation = false
+			return Step{Kind: StepPropagation, Incompatibility: conflict}
+		}
+	}
+
+	if s.applySearchPolicy() {
+		s.startPropagation(s.rootPkg)
+		return Step{Kind: StepRestart}
+	}
+
+	s.phase = phaseDecide
+	return Step{Kind: StepPropagation}
+}
+
+func (s *solver) conflictResolutionStep() Step {
+	learned, next, err := s.backjump(s.conflict)
+	if err != nil {
+		return s.fail(err)
+	}
+	if s.conflictInPropagation {
+		// Propagation continues from the derived term
+		s.phase = phasePropagate
+		s.propagation.changed = []string{next}
+		s.propagation.contradicted = append(s.propagation.contradicted, learned)
+	} else {
+		s.startPropagation(next)
+	}
+	s.conflict = nil
+	return Step{Kind: StepConflictResolution, Package: next, Incompatibility: learned}
+}
+
+func (s *solver) decisionStep() Step {
+	// Prefetch all positive undecided packages
+	undecided := s.partialSolution.allPositiveUndecided()
+	go func() {
+		for _, pkg := range undecided {
+			sourcePkg, ok := s.sourcePackage(pkg)
+			if !ok {
+				continue
+			}
+			go func(pkg string) {
+				_, _ = s.source.GetPackageVersions(pkg)
+			}(sourcePkg)
+		}
+	}()
+
+	next, done, err := s.decision()
+	if err != nil {
+		return s.fail(errors.Wrap(err, "failed to make decision"))
+	}
+	if done {
+		s.phase = phaseDone
+		return Step{Kind: StepSolved}
+	}
+	if err := s.checkLimits(); err != nil {
+		return s.fail(err)
+	}
+
+	s.startPropagation(next)
+	step := Step{Kind: StepDecision, Package: next}
+	if version, ok := s.partialSolution.decisionsMap()[next]; ok {
+		step.Version = &version
+	}
+	return step
+}
@@ -0,0 +1,250 @@
+package pubgrub
+
+import (
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// Solver solves a problem one step at a time, allowing to inspect its state between steps.
+// SolveWithOptions runs a Solver until it is done.
+//
+// The versions are first searched without the weak dependencies, so that they never cost a version of another package.
+// The search then starts again with the weak dependencies, keeping the versions found,
+// and each weak dependency involved in a conflict is dropped.
+type Solver struct {
+	source  Source
+	rootPkg string
+	options Options
+	prepare func(s *solver)
+
+	s *solver
+	// selected are the versions found without the weak dependencies, kept while adding them.
+	// It is nil until they are found.
+	selected                map[string]semver.Version
+	dropped                 []DroppedRecommendation
+	droppedWeakDependencies map[weakDependency]bool
+
+	done     bool
+	solution Solution
+	err      error
+}
+
+// Assignment is a decision or a derivation of the partial solution of a Solver
+type Assignment struct {
+	Package       string
+	DecisionLevel int
+	// Decision is set for decisions, which select Version
+	Decision bool
+	Version  semver.Version
+	// Term and Cause are set for derivations, which derive Term from the incompatibility Cause
+	Term  Term
+	Cause *Incompatibility
+}
+
+// NewSolver creates a Solver for the dependencies of rootPkg. No work is done until the first Step.
+func NewSolver(source Source, rootPkg string, options Options) *Solver {
+	return newStepwiseSolver(source, rootPkg, options, nil)
+}
+
+// newStepwiseSolver creates a Solver which calls prepare, if not nil, with each solver before it runs
+func newStepwiseSolver(source Source, rootPkg string, options Options, prepare func(s *solver)) *Solver {
+	sv := &Solver{
+		// Every retry, as well as any later query on the solution, reuses the versions fetched so far
+		source:                  newRecordingSource(source),
+		rootPkg:                 rootPkg,
+		options:                 options,
+		prepare:                 prepare,
+		droppedWeakDependencies: map[weakDependency]bool{},
+	}
+	sv.start()
+	return sv
+}
+
+// start creates a new solver, that only differs from the previous ones
+// by the versions selected without the weak dependencies and the weak dependencies dropped so far.
+// It keeps the incompatibilities of the previous solver that hold regardless of those, so that it does not learn them again,
+// and its statistics, so that the limits and the statistics cover the whole search.
+func (sv *Solver) start() {
+	previous := sv.s
+	s, err := newSolver(sv.source, sv.rootPkg, sv.options)
+	if err != nil {
+		// The search fails at its first step, with nothing done to inspect
+		s = &solver{rootPkg: sv.rootPkg, source: sv.source, options: sv.options}
+		s.fail(err)
+		sv.s = s
+		return
+	}
+	if previous != nil {
+		s.statistics = previous.statistics
+	}
+	s.droppedWeakDependencies = sv.droppedWeakDependencies
+	// The incompatibilities added by prepare take precedence over the equal ones added later
+	if sv.prepare != nil {
+		sv.prepare(s)
+	}
+	if sv.selected == nil {
+		s.ignoreWeakDependencies = true
+	} else {
+		s.addSelectedVersionIncompatibilities(sv.selected)
+	}
+	if usesKnowledgeStore(sv.options) {
+		sv.options.KnowledgeStore.seed(s)
+	}
+	if previous != nil {
+		memo := map[*Incompatibility]bool{}
+		for _, in := range previous.incompatibilities {
+			if in.kind != IncompatibilityKindRoot && holdsWithoutAssumptions(in, memo) {
+				s.addIncompatibility(in)
+			}
+		}
+	}
+	s.startPropagation(sv.rootPkg)
+	sv.s = s
+}
+
+// Step advances the search by a single step. Once the search is done, the last step is returned again.
+func (sv *Solver) Step() Step {
+	step := sv.s.step()
+	if sv.done {
+		return step
+	}
+
+	switch step.Kind {
+	case StepSolved:
+		sv.learn()
+		if sv.selected == nil && sv.s.hasWeakDependencies() {
+			sv.selected = sv.s.selectedVersions()
+			sv.start()
+			return Step{Kind: StepWeakDependenciesAdded}
+		}
+		sv.done = true
+		sv.solution = sv.s.solution(sv.dropped)
+	case StepFailed:
+		sv.learn()
+
+		// A weak dependency involved in the conflict is dropped, and the search starts again without it.
+		// Without the weak dependencies, the selected versions are a solution, so one is always involved.
+		var solvingError SolvingError
+		var weak *weakDependencyIncompatibility
+		if errors.As(step.Err, &solvingError) {
+			weak = findWeakDependency(solvingError.Cause())
+		}
+		if weak == nil {
+			sv.done = true
+			sv.err = step.Err
+			return step
+		}
+		sv.droppedWeakDependencies[weakDependency{dependant: weak.dependant, dependency: weak.dependency}] = true
+		sv.dropped = append(sv.dropped, DroppedRecommendation{
+			Dependant:  weak.dependant,
+			Dependency: weak.dependency,
+			Constraint: weak.constraint,
+			Reason:     solvingError,
+		})
+		sv.start()
+		return Step{Kind: StepWeakDependencyDropped, Package: weak.dependency, Err: solvingError}
+	default:
+	}
+	return step
+}
+
+// learn adds what the current search learned to the knowledge store
+func (sv *Solver) learn() {
+	if usesKnowledgeStore(sv.options) {
+		sv.options.KnowledgeStore.learn(sv.s)
+	}
+}
+
+// Solve steps through the search until it is done
+func (sv *Solver) Solve() (Solution, error) {
+	for !sv.done {
+		sv.Step()
+	}
+	return sv.Result()
+}
+
+// solveWithoutWeakDependencies steps through the search until the versions are found without the weak dependencies,
+// and returns the solver that found them, which can continue searching for other versions
+func (sv *Solver) solveWithoutWeakDependencies() (*solver, error) {
+	for {
+		s := sv.s
+		step := sv.Step()
+		switch step.Kind {
+		case StepWeakDependenciesAdded, StepSolved:
+			return s, nil
+		case StepFailed:
+			return nil, step.Err
+		default:
+		}
+	}
+}
+
+// solveWithWeakDependencies adds the weak dependencies to the versions found by s without them
+func (sv *Solver) solveWithWeakDependencies(s *solver) (Solution, error) {
+	if !s.hasWeakDependencies() {
+		return s.solution(nil), nil
+	}
+	sv.selected = s.selectedVersions()
+	sv.dropped = nil
+	sv.droppedWeakDependencies = map[weakDependency]bool{}
+	sv.done = false
+	sv.err = nil
+	// Start from s, to keep what it learned while searching for the versions
+	sv.s = s
+	sv.start()
+	return sv.Solve()
+}
+
+// Done returns whether the search ended
+func (sv *Solver) Done() bool {
+	return sv.done
+}
+
+// Result returns the solution or the error the search ended with
+func (sv *Solver) Result() (Solution, error) {
+	if !sv.done {
+		return Solution{}, errors.New("the search is not done")
+	}
+	return sv.solution, sv.err
+}
+
+// Assignments returns the decisions and derivations of the partial solution, in the order they were made
+func (sv *Solver) Assignments() []Assignment {
+	result := make([]Assignment, 0, len(sv.s.partialSolution.assignments))
+	for _, a := range sv.s.partialSolution.assignments {
+		switch a := a.(type) {
+		case decision:
+			result = append(result, Assignment{
+				Package:       a.pkg,
+				DecisionLevel: a.decisionLevel,
+				Decision:      true,
+				Version:       a.version,
+			})
+		case derivation:
+			result = append(result, Assignment{
+				Package:       a.t.pkg,
+				DecisionLevel: a.decisionLevel,
+				Term:          a.t,
+				Cause:         a.cause,
+			})
+		}
+	}
+	return result
+}
+
+// DecisionLevel returns the number of decisions in the partial solution
+func (sv *Solver) DecisionLevel() int {
+	return sv.s.partialSolution.currentDecisionLevel()
+}
+
+// Incompatibilities returns the incompatibilities known to the current search, in the order they were added
+func (sv *Solver) Incompatibilities() []*Incompatibility {
+	return slices.Clone(sv.s.incompatibilities)
+}
+
+// Statistics returns the work done by the search so far, including the searches before each restart
+func (sv *Solver) Statistics() Statistics {
+	return sv.s.statistics
+}
@@ -0,0 +1,175 @@
+package pubgrub
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+func TestSolver_Step(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("*"),
+						"bar": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"bar": newConstraint("^2.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+		},
+	}
+
+	solver := NewSolver(source, "$$root$$", Options{})
+
+	step := solver.Step()
+	testza.AssertEqual(t, StepPropagation, step.Kind)
+	testza.AssertEqual(t, []Assignment{
+		{
+			Package: "$$root$$",
+			Term:    Term{pkg: "$$root$$", versionConstraint: semver.AnyConstraint, positive: true},
+			Cause:   solver.Incompatibilities()[0],
+		},
+	}, solver.Assignments())
+	testza.AssertEqual(t, IncompatibilityKindRoot, solver.Incompatibilities()[0].Kind())
+
+	step = solver.Step()
+	testza.AssertEqual(t, StepDecision, step.Kind)
+	testza.AssertEqual(t, "$$root$$", step.Package)
+	testza.AssertEqual(t, newVersion("1.0.0"), *step.Version)
+	testza.AssertEqual(t, 1, solver.DecisionLevel())
+
+	var kinds []StepKind
+	for !solver.Done() {
+		step = solver.Step()
+		kinds = append(kinds, step.Kind)
+		if step.Kind == StepConflictResolution {
+			testza.AssertContains(t, solver.Incompatibilities(), step.Incompatibility)
+		}
+	}
+	testza.AssertEqual(t, []StepKind{
+		StepPropagation,
+		StepDecision,
+		StepPropagation,
+		StepConflictResolution,
+		StepPropagation,
+		StepDecision,
+		StepPropagation,
+		StepDecision,
+		StepPropagation,
+		StepSolved,
+	}, kinds)
+	testza.AssertEqual(t, StepSolved, solver.Step().Kind)
+
+	solution, err := solver.Result()
+	testza.AssertNoError(t, err)
+	expected, err := SolveWithOptions(source, "$$root$$", Options{})
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, expected.Versions, solution.Versions)
+	testza.AssertEqual(t, expected.Statistics, solution.Statistics)
+}
+
+func TestSolver_Step_KeepsLearnedIncompatibilities(t *testing.T) {
+	t.Parallel()
+
+	// foo 2.0.0 conflicts with its own dependencies, which is learned before the recommendation of bar is added and dropped
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("*"),
+						"bar": newConstraint("*"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"baz": newConstraint("^2.0.0"),
+						"qux": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+					WeakDependencies: map[string]semver.Constraint{
+						"qux": newConstraint("^2.0.0"),
+					},
+				},
+			},
+			"baz": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+			"qux": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"baz": newConstraint("^1.0.0"),
+					},
+				},
+			},
+		},
+	}
+
+	solver := NewSolver(source, "$$root$$", Options{})
+	var learned []*Incompatibility
+	var restarts []StepKind
+	for !solver.Done() {
+		previous := solver.s
+		step := solver.Step()
+		if step.Kind != StepWeakDependenciesAdded && step.Kind != StepWeakDependencyDropped {
+			continue
+		}
+		restarts = append(restarts, step.Kind)
+		for _, in := range previous.incompatibilities {
+			if in.kind == IncompatibilityKindDerived && holdsWithoutAssumptions(in, map[*Incompatibility]bool{}) {
+				learned = append(learned, in)
+			}
+		}
+		testza.AssertNotEqual(t, 0, len(learned))
+		for _, in := range learned {
+			testza.AssertContains(t, solver.s.incompatibilities, in)
+		}
+	}
+	testza.AssertEqual(t, []StepKind{StepWeakDependenciesAdded, StepWeakDependencyDropped}, restarts)
+
+	solution, err := solver.Result()
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, newVersion("1.0.0"), solution.Versions["foo"])
+	testza.AssertLen(t, solution.DroppedRecommendations, 1)
+}
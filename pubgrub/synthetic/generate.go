@@ -0,0 +1,172 @@
+package synthetic
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// Shape describes the registries made by Generate
+type Shape struct {
+	// Packages is the number of packages, besides the root
+	Packages int
+	// Versions is the number of versions of each package
+	Versions int
+	// RootDependencies is the number of packages the root depends on
+	RootDependencies int
+	// FanOut is the number of dependencies of each version.
+	// Packages only depend on the packages generated after them, so the last packages have fewer dependencies.
+	FanOut int
+	// ConstraintWidth is the number of consecutive versions allowed by a dependency, 0 allowing any version
+	ConstraintWidth int
+	// ConflictProbability is the probability of a dependency allowing a single version,
+	// which likely conflicts with the other dependencies on the same package
+	ConflictProbability float64
+	// PrereleaseRatio is the probability of a version being a prerelease
+	PrereleaseRatio float64
+	// Seed seeds the random choices, the same shape always generating the same registry
+	Seed int64
+}
+
+func (s Shape) validate() error {
+	if s.Packages < 1 || s.Versions < 1 {
+		return errors.New("at least one package and one version are required")
+	}
+	if s.RootDependencies < 0 || s.FanOut < 0 || s.ConstraintWidth < 0 {
+		return errors.New("the number of dependencies and the constraint width cannot be negative")
+	}
+	if s.ConflictProbability < 0 || s.ConflictProbability > 1 || s.PrereleaseRatio < 0 || s.PrereleaseRatio > 1 {
+		return errors.New("probabilities must be between 0 and 1")
+	}
+	return nil
+}
+
+// Generate makes a registry of the given shape, with packages named pkg0, pkg1, ... depended on by RootPackage
+func Generate(shape Shape) (*Registry, error) {
+	if err := shape.validate(); err != nil {
+		return nil, err
+	}
+	r := rand.New(rand.NewSource(shape.Seed)) //nolint:gosec
+
+	names := make([]string, shape.Packages)
+	versions := make([][]string, shape.Packages)
+	for i := range names {
+		names[i] = fmt.Sprintf("pkg%d", i)
+		versions[i] = make([]string, shape.Versions)
+		for v := range versions[i] {
+			// A few minor versions per major version, for the constraints to span major versions
+			versions[i][v] = fmt.Sprintf("%d.%d.0", v/4+1, v%4)
+			if r.Float64() < shape.PrereleaseRatio {
+				versions[i][v] += "-rc.1"
+			}
+		}
+	}
+
+	// The dependencies of later versions allow later versions of their dependencies,
+	// while the root dependencies allow any of them
+	dependencies := func(candidates []int, count int, position int) map[string]semver.Constraint {
+		deps := map[string]semver.Constraint{}
+		for _, i := range r.Perm(len(candidates))[:min(count, len(candidates))] {
+			dep := candidates[i]
+			depPosition := position
+			if depPosition < 0 {
+				depPosition = r.Intn(shape.Versions)
+			}
+			deps[names[dep]] = mustConstraint(randomConstraint(r, shape, versions[dep], depPosition))
+		}
+		return deps
+	}
+
+	allPackages := make([]int, shape.Packages)
+	for i := range allPackages {
+		allPackages[i] = i
+	}
+	packages := map[string][]pubgrub.PackageVersion{
+		RootPackage: {
+			{
+				Version:      mustVersion("1.0.0"),
+				Dependencies: dependencies(allPackages, shape.RootDependencies, -1),
+			},
+		},
+	}
+	for i, pkg := range names {
+		for position, v := range versions[i] {
+			packages[pkg] = append(packages[pkg], pubgrub.PackageVersion{
+				Version:      mustVersion(v),
+				Dependencies: dependencies(allPackages[i+1:], shape.FanOut, position),
+			})
+		}
+	}
+	return &Registry{packages: packages}, nil
+}
+
+// randomConstraint returns a constraint allowing ConstraintWidth consecutive versions of the dependency,
+// the version at position being one of them, or a single random version with ConflictProbability
+func randomConstraint(r *rand.Rand, shape Shape, versions []string, position int) string {
+	if r.Float64() < shape.ConflictProbability {
+		return versions[r.Intn(len(versions))]
+	}
+	if shape.ConstraintWidth == 0 || shape.ConstraintWidth >= len(versions) {
+		return "*"
+	}
+	start := min(max(position-r.Intn(shape.ConstraintWidth), 0), len(versions)-shape.ConstraintWidth)
+	end := start + shape.ConstraintWidth
+	if end == len(versions) {
+		return ">=" + versions[start]
+	}
+	return fmt.Sprintf(">=%s <%s", versions[start], versions[end])
+}
+
+// Pigeonhole makes a registry placing each pigeon package in the hole given by its version.
+// The version of each hole package is the pigeon in it, so no two pigeons can share a hole.
+// There is no solution when there are more pigeons than holes, which takes exponentially many conflicts to prove.
+func Pigeonhole(pigeons, holes int) *Registry {
+	root := pubgrub.PackageVersion{
+		Version:      mustVersion("1.0.0"),
+		Dependencies: map[string]semver.Constraint{},
+	}
+	packages := map[string][]pubgrub.PackageVersion{}
+	for p := 1; p <= pigeons; p++ {
+		pigeon := fmt.Sprintf("pigeon%d", p)
+		root.Dependencies[pigeon] = semver.AnyConstraint
+		for h := 1; h <= holes; h++ {
+			packages[pigeon] = append(packages[pigeon], pubgrub.PackageVersion{
+				Version: mustVersion(fmt.Sprintf("%d.0.0", h)),
+				Dependencies: map[string]semver.Constraint{
+					fmt.Sprintf("hole%d", h): mustConstraint(fmt.Sprintf("%d.0.0", p)),
+				},
+			})
+		}
+	}
+	for h := 1; h <= holes; h++ {
+		hole := fmt.Sprintf("hole%d", h)
+		for p := 1; p <= pigeons; p++ {
+			packages[hole] = append(packages[hole], pubgrub.PackageVersion{
+				Version: mustVersion(fmt.Sprintf("%d.0.0", p)),
+			})
+		}
+	}
+	packages[RootPackage] = []pubgrub.PackageVersion{root}
+	return &Registry{packages: packages}
+}
+
+// mustVersion parses a generated version, which is always valid
+func mustVersion(v string) semver.Version {
+	version, err := semver.NewVersion(v)
+	if err != nil {
+		panic(errors.Wrapf(err, "invalid generated version %s", v))
+	}
+	return version
+}
+
+// mustConstraint parses a generated constraint, which is always valid
+func mustConstraint(c string) semver.Constraint {
+	constraint, err := semver.NewConstraint(c)
+	if err != nil {
+		panic(errors.Wrapf(err, "invalid generated constraint %s", c))
+	}
+	return constraint
+}
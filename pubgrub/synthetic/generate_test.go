@@ -0,0 +1,240 @@
+package synthetic
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub"
+	"github.com/pkg/errors"
+)
+
+var shapes = map[string]Shape{
+	"small": {
+		Packages:         20,
+		Versions:         5,
+		RootDependencies: 3,
+		FanOut:           2,
+		ConstraintWidth:  3,
+	},
+	"medium": {
+		Packages:         100,
+		Versions:         10,
+		RootDependencies: 5,
+		FanOut:           3,
+		ConstraintWidth:  4,
+	},
+	"large": {
+		Packages:         200,
+		Versions:         15,
+		RootDependencies: 8,
+		FanOut:           3,
+		ConstraintWidth:  6,
+	},
+	"conflicting": {
+		Packages:            100,
+		Versions:            10,
+		RootDependencies:    5,
+		FanOut:              3,
+		ConstraintWidth:     4,
+		ConflictProbability: 0.2,
+	},
+	"prereleases": {
+		Packages:         100,
+		Versions:         10,
+		RootDependencies: 5,
+		FanOut:           3,
+		ConstraintWidth:  4,
+		PrereleaseRatio:  0.3,
+	},
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	shape := Shape{
+		Packages:         10,
+		Versions:         6,
+		RootDependencies: 4,
+		FanOut:           3,
+		ConstraintWidth:  2,
+		Seed:             1,
+	}
+	registry, err := Generate(shape)
+	testza.AssertNoError(t, err)
+
+	// The same shape generates the same registry
+	again, err := Generate(shape)
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, registry, again)
+
+	testza.AssertLen(t, registry.Packages(), 10)
+	root, err := registry.GetPackageVersions(RootPackage)
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, root[0].Dependencies, 4)
+
+	for _, pkg := range registry.Packages() {
+		versions, err := registry.GetPackageVersions(pkg)
+		testza.AssertNoError(t, err)
+		testza.AssertLen(t, versions, 6)
+		testza.AssertTrue(t, slices.IsSortedFunc(versions, func(a, b pubgrub.PackageVersion) int {
+			return a.Version.Compare(b.Version)
+		}))
+
+		index, _ := strconv.Atoi(strings.TrimPrefix(pkg, "pkg"))
+		for _, v := range versions {
+			testza.AssertEqual(t, min(3, 9-index), len(v.Dependencies), "dependencies of %s %s", pkg, v.Version)
+			for dep, constraint := range v.Dependencies {
+				depIndex, _ := strconv.Atoi(strings.TrimPrefix(dep, "pkg"))
+				testza.AssertGreater(t, depIndex, index)
+
+				depVersions, err := registry.GetPackageVersions(dep)
+				testza.AssertNoError(t, err)
+				allowed := 0
+				for _, depVersion := range depVersions {
+					if constraint.Contains(depVersion.Version) {
+						allowed++
+					}
+				}
+				testza.AssertEqual(t, 2, allowed, "%s %s depends on %s \"%s\"", pkg, v.Version, dep, constraint)
+			}
+		}
+	}
+
+	_, err = registry.GetPackageVersions("unknown")
+	testza.AssertNotNil(t, err)
+
+	// Every version is a prerelease, and every dependency allows a single version
+	shape.PrereleaseRatio = 1
+	shape.ConflictProbability = 1
+	registry, err = Generate(shape)
+	testza.AssertNoError(t, err)
+	for _, pkg := range registry.Packages() {
+		versions, _ := registry.GetPackageVersions(pkg)
+		for _, v := range versions {
+			testza.AssertTrue(t, v.Version.IsPrerelease())
+			for _, constraint := range v.Dependencies {
+				testza.AssertFalse(t, strings.ContainsAny(constraint.String(), "<>*"))
+			}
+		}
+	}
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, shape := range []Shape{
+		{},
+		{Packages: 1},
+		{Packages: 1, Versions: 1, FanOut: -1},
+		{Packages: 1, Versions: 1, ConflictProbability: 2},
+	} {
+		_, err := Generate(shape)
+		testza.AssertNotNil(t, err, "%+v", shape)
+	}
+}
+
+// TestGenerate_Solve checks that the solutions found for small generated registries are valid
+func TestGenerate_Solve(t *testing.T) {
+	t.Parallel()
+
+	seeds := 50
+	if testing.Short() {
+		seeds = 10
+	}
+	for _, shape := range []Shape{
+		shapes["small"],
+		{Packages: 20, Versions: 5, RootDependencies: 3, FanOut: 2, ConstraintWidth: 2, ConflictProbability: 0.3},
+		{Packages: 20, Versions: 5, RootDependencies: 3, FanOut: 2, ConstraintWidth: 3, PrereleaseRatio: 0.5},
+	} {
+		for seed := int64(0); seed < int64(seeds); seed++ {
+			shape.Seed = seed
+			registry, err := Generate(shape)
+			testza.AssertNoError(t, err)
+
+			solution, err := pubgrub.SolveWithOptions(registry, RootPackage, pubgrub.Options{})
+			if err != nil {
+				var solvingErr pubgrub.SolvingError
+				testza.AssertTrue(t, errors.As(err, &solvingErr), "%+v: %v", shape, err)
+				continue
+			}
+			violations, err := pubgrub.Verify(registry, RootPackage, solution.Versions)
+			testza.AssertNoError(t, err)
+			testza.AssertLen(t, violations, 0, "%+v: %v", shape, violations)
+		}
+	}
+}
+
+func TestPigeonhole(t *testing.T) {
+	t.Parallel()
+
+	solution, err := pubgrub.SolveWithOptions(Pigeonhole(3, 3), RootPackage, pubgrub.Options{})
+	testza.AssertNoError(t, err)
+	holes := map[int]bool{}
+	for p := 1; p <= 3; p++ {
+		hole := solution.Versions[fmt.Sprintf("pigeon%d", p)].Major()
+		testza.AssertEqual(t, p, solution.Versions[fmt.Sprintf("hole%d", hole)].Major())
+		holes[hole] = true
+	}
+	testza.AssertLen(t, holes, 3)
+
+	_, err = pubgrub.SolveWithOptions(Pigeonhole(4, 3), RootPackage, pubgrub.Options{})
+	var solvingErr pubgrub.SolvingError
+	testza.AssertTrue(t, errors.As(err, &solvingErr))
+}
+
+// benchmarkSolve solves the registry b.N times, reporting the work done by each solve
+func benchmarkSolve(b *testing.B, registry *Registry, options pubgrub.Options) {
+	b.Helper()
+
+	var statistics pubgrub.Statistics
+	for i := 0; i < b.N; i++ {
+		solver := pubgrub.NewSolver(registry, RootPackage, options)
+		_, _ = solver.Solve()
+		statistics = solver.Statistics()
+	}
+	b.ReportMetric(float64(statistics.Decisions), "decisions/op")
+	b.ReportMetric(float64(statistics.Conflicts), "conflicts/op")
+}
+
+func BenchmarkSolve(b *testing.B) {
+	names := make([]string, 0, len(shapes))
+	for name := range shapes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		registry, err := Generate(shapes[name])
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			benchmarkSolve(b, registry, pubgrub.Options{})
+		})
+	}
+}
+
+func BenchmarkSolve_Pigeonhole(b *testing.B) {
+	policies := []struct {
+		name   string
+		policy pubgrub.SearchPolicy
+	}{
+		{name: "default"},
+		{name: "restarts", policy: pubgrub.SearchPolicy{
+			ReduceInterval:  50,
+			KeepLearned:     100,
+			RestartInterval: 20,
+			RestartGrowth:   1.5,
+		}},
+	}
+	for holes := 3; holes <= 5; holes++ {
+		for _, p := range policies {
+			b.Run(fmt.Sprintf("%d/%d/%s", holes+1, holes, p.name), func(b *testing.B) {
+				benchmarkSolve(b, Pigeonhole(holes+1, holes), pubgrub.Options{SearchPolicy: p.policy})
+			})
+		}
+	}
+}
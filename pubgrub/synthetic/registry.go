@@ -0,0 +1,43 @@
+// Package synthetic generates registries of made-up packages, to benchmark and stress test the solver
+package synthetic
+
+import (
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub"
+	"github.com/mircearoata/pubgrub-go/pubgrub/helpers"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// RootPackage is the name of the root package of the generated registries
+const RootPackage = "$$root$$"
+
+// Registry is a Source of generated packages
+type Registry struct {
+	packages map[string][]pubgrub.PackageVersion
+}
+
+func (r *Registry) GetPackageVersions(pkg string) ([]pubgrub.PackageVersion, error) {
+	if versions, ok := r.packages[pkg]; ok {
+		return versions, nil
+	}
+	return nil, errors.Errorf("unknown package %s", pkg)
+}
+
+// PickVersion picks the latest release version, or the latest prerelease version if there are only prereleases
+func (r *Registry) PickVersion(_ string, versions []semver.Version) semver.Version {
+	return helpers.StandardVersionPriority(versions)
+}
+
+// Packages returns the names of the generated packages, without the root, sorted
+func (r *Registry) Packages() []string {
+	packages := make([]string, 0, len(r.packages))
+	for pkg := range r.packages {
+		if pkg != RootPackage {
+			packages = append(packages, pkg)
+		}
+	}
+	slices.Sort(packages)
+	return packages
+}
@@ -0,0 +1,124 @@
+package pubgrub
+
+import (
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// UnsatisfiableCore is a subset of the root requirements that cannot be satisfied together,
+// such that removing any one of them makes the rest satisfiable
+type UnsatisfiableCore struct {
+	Requirements map[string]semver.Constraint
+	// Error explains why the requirements of the core cannot be satisfied
+	Error SolvingError
+}
+
+// MinimalUnsatisfiableCore finds a minimal subset of the root requirements, that is the dependencies of the root
+// combined with Options.Requirements, that still has no solution.
+// If the problem has a solution, nil is returned.
+// The core is empty if the problem has no solution even without any root requirement.
+func MinimalUnsatisfiableCore(source Source, rootPkg string, options Options) (*UnsatisfiableCore, error) {
+	source = newRecordingSource(source)
+
+	requirements, err := rootRequirements(source, rootPkg, options)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs := make([]string, 0, len(requirements))
+	for pkg := range requirements {
+		pkgs = append(pkgs, pkg)
+	}
+	slices.Sort(pkgs)
+
+	check := func(subset []string) (*SolvingError, error) {
+		selected := make(map[string]semver.Constraint, len(subset))
+		for _, pkg := range subset {
+			selected[pkg] = requirements[pkg]
+		}
+		return solveRequirements(source, rootPkg, options, selected)
+	}
+
+	solvingError, err := check(pkgs)
+	if err != nil || solvingError == nil {
+		return nil, err
+	}
+
+	// The conflict might not involve any root requirement
+	var core []string
+	solvingError, err = check(nil)
+	if err != nil {
+		return nil, err
+	}
+	if solvingError == nil {
+		core, err = quickXplain(nil, false, pkgs, check)
+		if err != nil {
+			return nil, err
+		}
+		solvingError, err = check(core)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &UnsatisfiableCore{
+		Requirements: make(map[string]semver.Constraint, len(core)),
+		Error:        *solvingError,
+	}
+	for _, pkg := range core {
+		result.Requirements[pkg] = requirements[pkg]
+	}
+	return result, nil
+}
+
+// quickXplain returns a minimal subset of candidates which, together with background, is unsatisfiable.
+// The background and all candidates together must be unsatisfiable, while the background alone must be satisfiable.
+func quickXplain(background []string, backgroundChanged bool, candidates []string, check func([]string) (*SolvingError, error)) ([]string, error) {
+	if backgroundChanged {
+		solvingError, err := check(background)
+		if err != nil {
+			return nil, err
+		}
+		if solvingError != nil {
+			return nil, nil
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates, nil
+	}
+
+	half := len(candidates) / 2
+	first, second := candidates[:half], candidates[half:]
+
+	secondCore, err := quickXplain(concat(background, first), true, second, check)
+	if err != nil {
+		return nil, err
+	}
+	firstCore, err := quickXplain(concat(background, secondCore), len(secondCore) > 0, first, check)
+	if err != nil {
+		return nil, err
+	}
+	return concat(firstCore, secondCore), nil
+}
+
+func concat(a, b []string) []string {
+	result := make([]string, 0, len(a)+len(b))
+	result = append(result, a...)
+	return append(result, b...)
+}
+
+// solveRequirements solves the problem with the root requiring exactly the given requirements,
+// returning the SolvingError if there is no solution
+func solveRequirements(source Source, rootPkg string, options Options, requirements map[string]semver.Constraint) (*SolvingError, error) {
+	_, err := SolveWithOptions(source, rootPkg, withRootRequirements(options, requirements))
+	if err == nil {
+		return nil, nil
+	}
+	var solvingError SolvingError
+	if errors.As(err, &solvingError) {
+		return &solvingError, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,106 @@
+package pubgrub
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+func TestMinimalUnsatisfiableCore(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"a": newConstraint("^1.0.0"),
+						"b": newConstraint("^1.0.0"),
+						"c": newConstraint("^1.0.0"),
+						"d": newConstraint("^1.0.0"),
+						"e": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"a": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"x": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"b": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+			"c": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"x": newConstraint(">=1.0.0"),
+					},
+				},
+			},
+			"d": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+			"e": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"x": newConstraint("^2.0.0"),
+					},
+				},
+			},
+			"x": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+		},
+	}
+
+	core, err := MinimalUnsatisfiableCore(source, "$$root$$", Options{})
+	testza.AssertNoError(t, err)
+	testza.AssertNotNil(t, core)
+	testza.AssertEqual(t, map[string]semver.Constraint{
+		"a": newConstraint("^1.0.0"),
+		"e": newConstraint("^1.0.0"),
+	}, core.Requirements)
+	testza.AssertEqual(t, `Because every version of e depends on x "^2.0.0" and every version of a depends on x "^1.0.0", every version of a forbids e.
+So, because installing a "^1.0.0", version solving failed.`, core.Error.Error())
+
+	core, err = MinimalUnsatisfiableCore(source, "$$root$$", Options{
+		Requirements: map[string]semver.Constraint{
+			"x": newConstraint("^1.0.0"),
+		},
+		RootDependencies: map[string]semver.Constraint{
+			"b": newConstraint("^1.0.0"),
+			"e": newConstraint("^1.0.0"),
+		},
+	})
+	testza.AssertNoError(t, err)
+	testza.AssertNotNil(t, core)
+	testza.AssertEqual(t, map[string]semver.Constraint{
+		"e": newConstraint("^1.0.0"),
+		"x": newConstraint("^1.0.0"),
+	}, core.Requirements)
+
+	core, err = MinimalUnsatisfiableCore(source, "$$root$$", Options{
+		RootDependencies: map[string]semver.Constraint{
+			"a": newConstraint("^1.0.0"),
+			"b": newConstraint("^1.0.0"),
+		},
+	})
+	testza.AssertNoError(t, err)
+	testza.AssertNil(t, core)
+}
@@ -0,0 +1,278 @@
+package pubgrub
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// ViolationKind is the kind of rule broken by a set of selected versions
+type ViolationKind int
+
+const (
+	// ViolationUnknownVersion is a selected version that does not exist in the Source
+	ViolationUnknownVersion ViolationKind = iota
+	// ViolationMissingDependency is a dependency of a selected version on a package that is not selected
+	ViolationMissingDependency
+	// ViolationUnsatisfiedDependency is a dependency or optional dependency of a selected version
+	// on a package selected at a version outside its constraint
+	ViolationUnsatisfiedDependency
+	// ViolationUnreachable is a selected package that is not a dependency of the root, directly or transitively
+	ViolationUnreachable
+	// ViolationConstraint is a selected version outside the project constraint of its package
+	ViolationConstraint
+	// ViolationVersionGroup is a package selected at a different version than the other packages of its version group
+	ViolationVersionGroup
+)
+
+func (k ViolationKind) String() string {
+	switch k {
+	case ViolationUnknownVersion:
+		return "unknown version"
+	case ViolationMissingDependency:
+		return "missing dependency"
+	case ViolationUnsatisfiedDependency:
+		return "unsatisfied dependency"
+	case ViolationUnreachable:
+		return "unreachable"
+	case ViolationConstraint:
+		return "constraint"
+	case ViolationVersionGroup:
+		return "version group"
+	}
+	return "unknown"
+}
+
+// Violation is a rule broken by a set of selected versions
+type Violation struct {
+	Kind ViolationKind
+	// Package is the package the violation is about, and Version its selected version, or nil if it is not selected
+	Package string
+	Version *semver.Version
+	// Dependant and DependencyKind are the package declaring the dependency, for dependency violations
+	Dependant      string
+	DependencyKind DependencyKind
+	// Constraint is the constraint of the dependency, or the project constraint for ViolationConstraint
+	Constraint semver.Constraint
+	// Group is the version group, and GroupVersion the version of its other packages, for ViolationVersionGroup
+	Group        string
+	GroupVersion *semver.Version
+}
+
+func (v Violation) String() string {
+	depends := "depends on"
+	if v.DependencyKind == DependencyKindOptional {
+		depends = "optionally depends on"
+	}
+	switch v.Kind {
+	case ViolationUnknownVersion:
+		return fmt.Sprintf("%s %s is selected, but is not a version of %s", v.Package, v.Version, v.Package)
+	case ViolationMissingDependency:
+		return fmt.Sprintf("%s %s %s \"%s\", which is not selected", v.Dependant, depends, v.Package, v.Constraint)
+	case ViolationUnsatisfiedDependency:
+		return fmt.Sprintf("%s %s %s \"%s\", but %s %s is selected", v.Dependant, depends, v.Package, v.Constraint, v.Package, v.Version)
+	case ViolationUnreachable:
+		return fmt.Sprintf("%s %s is selected, but nothing depends on it", v.Package, v.Version)
+	case ViolationConstraint:
+		return fmt.Sprintf("%s %s is selected, but is constrained to \"%s\"", v.Package, v.Version, v.Constraint)
+	case ViolationVersionGroup:
+		return fmt.Sprintf("%s %s is selected, but the other packages of group %s are at %s", v.Package, v.Version, v.Group, v.GroupVersion)
+	}
+	return "unknown violation"
+}
+
+// Verify independently checks that the selected versions satisfy every dependency and optional dependency
+// of every selected version, and that every selected package is a dependency of the root.
+// The root may be missing from versions, in which case the version the Source picks is used.
+// It is meant to check solutions, including those found by other tools, and returns the violations sorted by package.
+func Verify(source Source, rootPkg string, versions map[string]semver.Version) ([]Violation, error) {
+	return VerifyWithOptions(source, rootPkg, versions, Options{})
+}
+
+// VerifyWithOptions is Verify for a solution of SolveWithOptions,
+// additionally checking the root requirements, the project constraints and the version groups of options.
+// Solutions using compatibility slots cannot be verified.
+func VerifyWithOptions(source Source, rootPkg string, versions map[string]semver.Version, options Options) ([]Violation, error) {
+	if len(options.CompatibilitySlots) > 0 {
+		return nil, errors.New("solutions using compatibility slots cannot be verified")
+	}
+
+	rootData, err := verifiedRoot(source, rootPkg, versions, options)
+	if err != nil {
+		return nil, err
+	}
+	selected := map[string]semver.Version{rootPkg: rootData.Version}
+	maps.Copy(selected, versions)
+
+	var violations []Violation
+	data := map[string]PackageVersion{rootPkg: rootData}
+	for pkg, v := range versions {
+		if pkg == rootPkg {
+			continue
+		}
+		pkgVersions, err := source.GetPackageVersions(pkg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get versions of %s", pkg)
+		}
+		i := slices.IndexFunc(pkgVersions, func(pv PackageVersion) bool {
+			return pv.Version.Compare(v) == 0
+		})
+		if i < 0 {
+			violations = append(violations, Violation{Kind: ViolationUnknownVersion, Package: pkg, Version: versionPtr(v)})
+			continue
+		}
+		data[pkg] = pkgVersions[i]
+	}
+
+	for pkg, pv := range data {
+		violations = append(violations, dependencyViolations(pkg, pv.Dependencies, DependencyKindRequired, selected)...)
+		violations = append(violations, dependencyViolations(pkg, pv.OptionalDependencies, DependencyKindOptional, selected)...)
+	}
+
+	// Only required and kept weak dependencies cause a package to be selected
+	reachable := map[string]bool{rootPkg: true}
+	queue := []string{rootPkg}
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		for _, deps := range []map[string]semver.Constraint{data[pkg].Dependencies, data[pkg].WeakDependencies} {
+			for dep, constraint := range deps {
+				v, ok := selected[dep]
+				if !ok || reachable[dep] || !constraint.Contains(v) {
+					continue
+				}
+				reachable[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+	for pkg, v := range versions {
+		if !reachable[pkg] {
+			violations = append(violations, Violation{Kind: ViolationUnreachable, Package: pkg, Version: versionPtr(v)})
+		}
+	}
+
+	for pkg, constraint := range options.Constraints {
+		if v, ok := versions[pkg]; ok && pkg != rootPkg && !constraint.Contains(v) {
+			violations = append(violations, Violation{Kind: ViolationConstraint, Package: pkg, Version: versionPtr(v), Constraint: constraint})
+		}
+	}
+
+	for _, group := range options.VersionGroups {
+		violations = append(violations, versionGroupViolations(group, versions)...)
+	}
+
+	slices.SortStableFunc(violations, func(a, b Violation) int {
+		if c := cmp.Compare(a.Package, b.Package); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Kind, b.Kind); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Dependant, b.Dependant)
+	})
+	return violations, nil
+}
+
+// verifiedRoot returns the data of the root version, with the root requirements of options as its dependencies
+func verifiedRoot(source Source, rootPkg string, versions map[string]semver.Version, options Options) (PackageVersion, error) {
+	rootVersions, err := source.GetPackageVersions(rootPkg)
+	if err != nil {
+		return PackageVersion{}, errors.Wrapf(err, "failed to get versions of %s", rootPkg)
+	}
+	if len(rootVersions) == 0 {
+		return PackageVersion{}, errors.Errorf("no versions of %s", rootPkg)
+	}
+
+	rootVersion, ok := versions[rootPkg]
+	if !ok {
+		allVersions := make([]semver.Version, len(rootVersions))
+		for i, v := range rootVersions {
+			allVersions[i] = v.Version
+		}
+		slices.SortFunc(allVersions, func(a, b semver.Version) int {
+			return a.Compare(b)
+		})
+		rootVersion = source.PickVersion(rootPkg, allVersions)
+	}
+	i := slices.IndexFunc(rootVersions, func(pv PackageVersion) bool {
+		return pv.Version.Compare(rootVersion) == 0
+	})
+	if i < 0 {
+		return PackageVersion{}, errors.Errorf("%s %s is not a version of %s", rootPkg, rootVersion, rootPkg)
+	}
+
+	root := rootVersions[i]
+	if options.RootDependencies == nil {
+		options.RootDependencies = root.Dependencies
+		if options.RootDependencies == nil {
+			options.RootDependencies = map[string]semver.Constraint{}
+		}
+	}
+	root.Dependencies, err = rootRequirements(source, rootPkg, options)
+	if err != nil {
+		return PackageVersion{}, err
+	}
+	return root, nil
+}
+
+func dependencyViolations(dependant string, deps map[string]semver.Constraint, kind DependencyKind, selected map[string]semver.Version) []Violation {
+	var violations []Violation
+	for dep, constraint := range deps {
+		v, ok := selected[dep]
+		switch {
+		case !ok && kind == DependencyKindRequired:
+			violations = append(violations, Violation{
+				Kind:           ViolationMissingDependency,
+				Package:        dep,
+				Dependant:      dependant,
+				DependencyKind: kind,
+				Constraint:     constraint,
+			})
+		case ok && !constraint.Contains(v):
+			violations = append(violations, Violation{
+				Kind:           ViolationUnsatisfiedDependency,
+				Package:        dep,
+				Version:        versionPtr(v),
+				Dependant:      dependant,
+				DependencyKind: kind,
+				Constraint:     constraint,
+			})
+		}
+	}
+	return violations
+}
+
+// versionGroupViolations reports the selected packages of the group that are not at the version of its first selected package
+func versionGroupViolations(group VersionGroup, versions map[string]semver.Version) []Violation {
+	var violations []Violation
+	var groupVersion *semver.Version
+	for _, pkg := range group.Packages {
+		v, ok := versions[pkg]
+		if !ok {
+			continue
+		}
+		if groupVersion == nil {
+			groupVersion = versionPtr(v)
+			continue
+		}
+		if v.Compare(*groupVersion) != 0 {
+			violations = append(violations, Violation{
+				Kind:         ViolationVersionGroup,
+				Package:      pkg,
+				Version:      versionPtr(v),
+				Group:        group.Name,
+				GroupVersion: groupVersion,
+			})
+		}
+	}
+	return violations
+}
+
+func versionPtr(v semver.Version) *semver.Version {
+	return &v
+}
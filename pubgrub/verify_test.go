@@ -0,0 +1,220 @@
+package pubgrub
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+// assertVerified checks that the solution found by the solver has no violations
+func assertVerified(t *testing.T, source Source, rootPkg string, versions map[string]semver.Version, options Options) {
+	t.Helper()
+
+	violations, err := VerifyWithOptions(source, rootPkg, versions, options)
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, 0, len(violations), "violations: %v", violations)
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+						"bar": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"baz": newConstraint("^1.0.0"),
+					},
+					OptionalDependencies: map[string]semver.Constraint{
+						"opt": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"qux": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"baz": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+			"qux": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+			"opt": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("2.0.0"),
+				},
+			},
+			"extra": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{})
+	testza.AssertNoError(t, err)
+	assertVerified(t, source, "$$root$$", solution.Versions, Options{})
+
+	violations, err := Verify(source, "$$root$$", map[string]semver.Version{
+		"foo":   newVersion("1.0.0"),
+		"baz":   newVersion("2.0.0"),
+		"opt":   newVersion("2.0.0"),
+		"extra": newVersion("1.0.0"),
+		"qux":   newVersion("3.0.0"),
+	})
+	testza.AssertNoError(t, err)
+
+	var messages []string
+	for _, v := range violations {
+		messages = append(messages, v.String())
+	}
+	testza.AssertEqual(t, []string{
+		"$$root$$ depends on bar \"^1.0.0\", which is not selected",
+		"foo depends on baz \"^1.0.0\", but baz 2.0.0 is selected",
+		"baz 2.0.0 is selected, but nothing depends on it",
+		"extra 1.0.0 is selected, but nothing depends on it",
+		"foo optionally depends on opt \"^1.0.0\", but opt 2.0.0 is selected",
+		"opt 2.0.0 is selected, but nothing depends on it",
+		"qux 3.0.0 is selected, but is not a version of qux",
+		"qux 3.0.0 is selected, but nothing depends on it",
+	}, messages)
+	testza.AssertEqual(t, ViolationMissingDependency, violations[0].Kind)
+	testza.AssertEqual(t, "bar", violations[0].Package)
+	testza.AssertNil(t, violations[0].Version)
+}
+
+func TestVerifyWithOptions(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+			"app": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"ui-core":  newConstraint("*"),
+						"ui-icons": newConstraint("*"),
+					},
+				},
+			},
+			"ui-core": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("1.1.0"),
+				},
+			},
+			"ui-icons": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+				{
+					Version: newVersion("1.1.0"),
+				},
+			},
+		},
+	}
+	options := Options{
+		Requirements: map[string]semver.Constraint{
+			"app": newConstraint("^1.0.0"),
+		},
+		Constraints: map[string]semver.Constraint{
+			"ui-icons": newConstraint("<1.1.0"),
+		},
+		VersionGroups: []VersionGroup{
+			{Name: "ui", Packages: []string{"ui-core", "ui-icons"}},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", options)
+	testza.AssertNoError(t, err)
+	assertVerified(t, source, "$$root$$", solution.Versions, options)
+
+	violations, err := VerifyWithOptions(source, "$$root$$", map[string]semver.Version{
+		"app":      newVersion("1.0.0"),
+		"ui-core":  newVersion("1.0.0"),
+		"ui-icons": newVersion("1.1.0"),
+	}, options)
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, violations, 2)
+	testza.AssertEqual(t, "ui-icons 1.1.0 is selected, but is constrained to \"<1.1.0\"", violations[0].String())
+	testza.AssertEqual(t, "ui-icons 1.1.0 is selected, but the other packages of group ui are at 1.0.0", violations[1].String())
+
+	// Without the requirements, nothing depends on app
+	violations, err = Verify(source, "$$root$$", solution.Versions)
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, violations, 3)
+	testza.AssertEqual(t, ViolationUnreachable, violations[0].Kind)
+	testza.AssertEqual(t, "app", violations[0].Package)
+
+	_, err = VerifyWithOptions(source, "$$root$$", solution.Versions, Options{
+		CompatibilitySlots: map[string][]CompatibilitySlot{
+			"ui-core": {{Name: "1", Constraint: newConstraint("^1.0.0")}},
+		},
+	})
+	testza.AssertNotNil(t, err)
+}
+
+func TestVerify_UnsortedRootVersions(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+					},
+				},
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.0.0"),
+				},
+			},
+		},
+	}
+
+	violations, err := Verify(source, "$$root$$", map[string]semver.Version{
+		"foo": newVersion("1.0.0"),
+	})
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, violations, 0)
+}
@@ -0,0 +1,89 @@
+package pubgrub
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+func (s *solver) indexVersionGroups() {
+	s.versionGroups = make(map[string]VersionGroup, len(s.options.VersionGroups))
+	s.versionGroupOf = make(map[string]VersionGroup)
+	for _, group := range s.options.VersionGroups {
+		s.versionGroups[group.Name] = group
+		for _, pkg := range group.Packages {
+			s.versionGroupOf[pkg] = group
+		}
+	}
+}
+
+// undecidedVersionGroupTerm returns the term of the group of the package of t, restricted to the versions allowed by t.
+// If the package is not in a group, or the version of the group is already decided, nil is returned.
+func (s *solver) undecidedVersionGroupTerm(t Term) *Term {
+	group, ok := s.versionGroupOf[t.pkg]
+	if !ok || s.partialSolution.isDecided(group.Name) {
+		return nil
+	}
+	groupTerm := Term{
+		pkg:               group.Name,
+		versionConstraint: t.versionConstraint,
+		positive:          true,
+	}
+	if existing := s.partialSolution.get(group.Name); existing != nil {
+		groupTerm = groupTerm.intersect(*existing)
+	}
+	return &groupTerm
+}
+
+// getVersionGroupVersions returns the versions of the virtual package of a group.
+// Each version is available if any package of the group has it,
+// and it optionally depends on exactly that version of every package of the group.
+func (s *solver) getVersionGroupVersions(group VersionGroup) ([]PackageVersion, error) {
+	var allVersions []semver.Version
+	for _, pkg := range group.Packages {
+		versions, err := s.getSourceVersions(pkg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get versions of %s in version group %s", pkg, group.Name)
+		}
+		for _, v := range versions {
+			allVersions = append(allVersions, v.Version)
+		}
+	}
+
+	slices.SortFunc(allVersions, func(a, b semver.Version) int {
+		return a.Compare(b)
+	})
+	allVersions = slices.CompactFunc(allVersions, func(a, b semver.Version) bool {
+		return a.Compare(b) == 0
+	})
+
+	result := make([]PackageVersion, 0, len(allVersions))
+	for _, v := range allVersions {
+		deps := make(map[string]semver.Constraint, len(group.Packages))
+		for _, pkg := range group.Packages {
+			deps[pkg] = semver.SingleVersionConstraint(v)
+		}
+		result = append(result, PackageVersion{
+			Version:              v,
+			OptionalDependencies: deps,
+		})
+	}
+	return result, nil
+}
+
+// withVersionGroupDependency makes each version of a package of the group depend on the same version of the group
+func withVersionGroupDependency(versions []PackageVersion, group VersionGroup) []PackageVersion {
+	result := make([]PackageVersion, 0, len(versions))
+	for _, v := range versions {
+		deps := maps.Clone(v.Dependencies)
+		if deps == nil {
+			deps = make(map[string]semver.Constraint, 1)
+		}
+		deps[group.Name] = semver.SingleVersionConstraint(v.Version)
+		v.Dependencies = deps
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package pubgrub
+
+import (
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+type weakDependency struct {
+	dependant  string
+	dependency string
+}
+
+type weakDependencyIncompatibility struct {
+	dependant  string
+	dependency string
+	constraint semver.Constraint
+}
+
+// findWeakDependency returns the first weak dependency incompatibility the derivation of in relies on, or nil if there is none
+func findWeakDependency(in *Incompatibility) *weakDependencyIncompatibility {
+	return findWeakDependencyVisited(in, map[*Incompatibility]bool{})
+}
+
+func findWeakDependencyVisited(in *Incompatibility, visited map[*Incompatibility]bool) *weakDependencyIncompatibility {
+	if visited[in] {
+		return nil
+	}
+	visited[in] = true
+	if in.Kind() == IncompatibilityKindWeakDependency {
+		for _, t := range in.Terms() {
+			if t.Dependency() != in.dependant {
+				return &weakDependencyIncompatibility{
+					dependant:  in.dependant,
+					dependency: t.Dependency(),
+					constraint: t.Constraint(),
+				}
+			}
+		}
+	}
+	for _, c := range in.Causes() {
+		if weak := findWeakDependencyVisited(c, visited); weak != nil {
+			return weak
+		}
+	}
+	return nil
+}
+
+// hasWeakDependencies returns whether any decided version has weak dependencies
+func (s *solver) hasWeakDependencies() bool {
+	for _, a := range s.partialSolution.assignments {
+		if dec, ok := a.(decision); ok && len(s.chosenVersions[dec.pkg].WeakDependencies) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// selectedVersions returns the decided versions of all packages except the root, including the virtual ones
+func (s *solver) selectedVersions() map[string]semver.Version {
+	result := s.partialSolution.decisionsMap()
+	delete(result, s.rootPkg)
+	return result
+}
+
+// addSelectedVersionIncompatibilities forbids any other version of the selected packages,
+// so that adding the weak dependencies can only select more packages
+func (s *solver) addSelectedVersionIncompatibilities(selected map[string]semver.Version) {
+	// Add them in a deterministic order (alphabetical)
+	pkgs := make([]string, 0, len(selected))
+	for pkg := range selected {
+		pkgs = append(pkgs, pkg)
+	}
+	slices.Sort(pkgs)
+	for _, pkg := range pkgs {
+		s.addIncompatibility(&Incompatibility{
+			terms: map[string]Term{
+				pkg: {
+					pkg:               pkg,
+					versionConstraint: semver.SingleVersionConstraint(selected[pkg]).Inverse(),
+					positive:          true,
+				},
+			},
+			kind: IncompatibilityKindSelectedVersion,
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package pubgrub
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DependencyPath is a chain of dependencies from the root to a package
+type DependencyPath []DependencyEdge
+
+// WhyIncluded returns every dependency path from the root to pkg that does not visit a package twice,
+// in a deterministic order. It returns nil if pkg is not part of the solution.
+func (s Solution) WhyIncluded(pkg string) []DependencyPath {
+	if _, ok := s.Versions[pkg]; !ok && pkg != s.Root {
+		return nil
+	}
+
+	dependants := s.dependantEdges()
+
+	var paths []DependencyPath
+	visited := map[string]bool{pkg: true}
+	var walk func(current string, reversePath []DependencyEdge)
+	walk = func(current string, reversePath []DependencyEdge) {
+		if current == s.Root {
+			path := slices.Clone(reversePath)
+			slices.Reverse(path)
+			paths = append(paths, path)
+			return
+		}
+		for _, e := range dependants[current] {
+			if visited[e.Dependant] {
+				continue
+			}
+			visited[e.Dependant] = true
+			walk(e.Dependant, append(reversePath, e))
+			delete(visited, e.Dependant)
+		}
+	}
+	walk(pkg, nil)
+
+	return paths
+}
+
+// WhyIncludedTree renders the packages depending on pkg as an inverted tree ending at the root,
+// similar to `cargo tree -i` and `npm why`
+func (s Solution) WhyIncludedTree(pkg string) string {
+	if _, ok := s.Versions[pkg]; !ok && pkg != s.Root {
+		return fmt.Sprintf("%s is not part of the solution", pkg)
+	}
+
+	dependants := s.dependantEdges()
+
+	lines := []string{s.packageString(pkg)}
+	visited := map[string]bool{pkg: true}
+	var walk func(current string, indent string)
+	walk = func(current string, indent string) {
+		edges := dependants[current]
+		for i, e := range edges {
+			branch, childIndent := "├── ", "│   "
+			if i == len(edges)-1 {
+				branch, childIndent = "└── ", "    "
+			}
+			line := fmt.Sprintf("%s%s%s requires \"%s\"", indent, branch, s.packageString(e.Dependant), e.Constraint)
+			if visited[e.Dependant] {
+				lines = append(lines, line+" (cycle)")
+				continue
+			}
+			lines = append(lines, line)
+			visited[e.Dependant] = true
+			walk(e.Dependant, indent+childIndent)
+			delete(visited, e.Dependant)
+		}
+	}
+	walk(pkg, "")
+
+	return strings.Join(lines, "\n")
+}
+
+// dependantEdges groups the edges that can bring a package into the solution by the package depended on
+func (s Solution) dependantEdges() map[string][]DependencyEdge {
+	result := map[string][]DependencyEdge{}
+	// Edges are sorted by dependant, so each group is sorted as well
+	for _, e := range s.Edges {
+		if e.Kind == DependencyKindOptional {
+			continue
+		}
+		result[e.Dependency] = append(result[e.Dependency], e)
+	}
+	return result
+}
+
+func (s Solution) packageString(pkg string) string {
+	if v, ok := s.Versions[pkg]; ok {
+		return fmt.Sprintf("%s %s", pkg, v)
+	}
+	return pkg
+}
@@ -0,0 +1,119 @@
+package pubgrub
+
+import (
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+func TestSolution_WhyIncluded(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+						"bar": newConstraint("^2.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.2.0"),
+					Dependencies: map[string]semver.Constraint{
+						"left-pad": newConstraint("^0.3.0"),
+					},
+				},
+			},
+			"bar": {
+				{
+					Version: newVersion("2.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo":      newConstraint("^1.1.0"),
+						"left-pad": newConstraint(">=0.2.0"),
+					},
+				},
+			},
+			"left-pad": {
+				{
+					Version: newVersion("0.3.0"),
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{})
+	testza.AssertNoError(t, err)
+
+	paths := solution.WhyIncluded("left-pad")
+	expected := []DependencyPath{
+		{
+			{Dependant: "$$root$$", Dependency: "bar", Constraint: newConstraint("^2.0.0")},
+			{Dependant: "bar", Dependency: "left-pad", Constraint: newConstraint(">=0.2.0")},
+		},
+		{
+			{Dependant: "$$root$$", Dependency: "foo", Constraint: newConstraint("^1.0.0")},
+			{Dependant: "foo", Dependency: "left-pad", Constraint: newConstraint("^0.3.0")},
+		},
+		{
+			{Dependant: "$$root$$", Dependency: "bar", Constraint: newConstraint("^2.0.0")},
+			{Dependant: "bar", Dependency: "foo", Constraint: newConstraint("^1.1.0")},
+			{Dependant: "foo", Dependency: "left-pad", Constraint: newConstraint("^0.3.0")},
+		},
+	}
+	testza.AssertEqual(t, expected, paths)
+
+	expectedTree := `left-pad 0.3.0
+├── bar 2.0.0 requires ">=0.2.0"
+│   └── $$root$$ requires "^2.0.0"
+└── foo 1.2.0 requires "^0.3.0"
+    ├── $$root$$ requires "^1.0.0"
+    └── bar 2.0.0 requires "^1.1.0"
+        └── $$root$$ requires "^2.0.0"`
+	testza.AssertEqual(t, expectedTree, solution.WhyIncludedTree("left-pad"))
+
+	testza.AssertNil(t, solution.WhyIncluded("missing"))
+}
+
+func TestSolution_WhyIncluded_Requirements(t *testing.T) {
+	t.Parallel()
+
+	source := mockSource{
+		packages: map[string][]PackageVersion{
+			"$$root$$": {
+				{
+					Version: newVersion("1.0.0"),
+					Dependencies: map[string]semver.Constraint{
+						"foo": newConstraint("^1.0.0"),
+					},
+				},
+			},
+			"foo": {
+				{
+					Version: newVersion("1.1.0"),
+				},
+				{
+					Version: newVersion("1.2.0"),
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{
+		Requirements: map[string]semver.Constraint{
+			"foo": newConstraint("<1.2.0"),
+		},
+	})
+	testza.AssertNoError(t, err)
+
+	testza.AssertLen(t, solution.Edges, 1)
+	paths := solution.WhyIncluded("foo")
+	testza.AssertLen(t, paths, 1)
+	testza.AssertLen(t, paths[0], 1)
+	testza.AssertEqual(t, DependencyKindRequired, paths[0][0].Kind)
+	testza.AssertTrue(t, paths[0][0].Constraint.Equal(newConstraint(">=1.0.0 <1.2.0")))
+}
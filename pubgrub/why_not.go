@@ -0,0 +1,47 @@
+package pubgrub
+
+import (
+	"maps"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+// UpgradeExplanation is the result of Solution.WhyNot
+type UpgradeExplanation struct {
+	// Solution is a solution selecting the requested version, if there is one
+	Solution *Solution
+	// Conflict explains why the requested version cannot be selected otherwise
+	Conflict *SolvingError
+}
+
+// WhyNot solves the problem again, additionally requiring pkg at exactly the given version.
+// The versions fetched while finding this solution are reused, so it fails with ErrNoSource
+// if the solution was not returned by the solver.
+func (s Solution) WhyNot(pkg string, version semver.Version) (UpgradeExplanation, error) {
+	source, err := s.solvedSource()
+	if err != nil {
+		return UpgradeExplanation{}, err
+	}
+
+	options := s.options
+	options.Requirements = maps.Clone(options.Requirements)
+	if options.Requirements == nil {
+		options.Requirements = map[string]semver.Constraint{}
+	}
+	required := semver.SingleVersionConstraint(version)
+	if existing, ok := options.Requirements[pkg]; ok {
+		required = existing.Intersect(required)
+	}
+	options.Requirements[pkg] = required
+
+	solution, err := SolveWithOptions(source, s.Root, options)
+	if err != nil {
+		var solvingError SolvingError
+		if errors.As(err, &solvingError) {
+			return UpgradeExplanation{Conflict: &solvingError}, nil
+		}
+		return UpgradeExplanation{}, err
+	}
+	return UpgradeExplanation{Solution: &solution}, nil
+}
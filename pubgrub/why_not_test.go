@@ -0,0 +1,91 @@
+package pubgrub
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/pkg/errors"
+)
+
+type countingSource struct {
+	Source
+	calls atomic.Int32
+}
+
+func (s *countingSource) GetPackageVersions(pkg string) ([]PackageVersion, error) {
+	s.calls.Add(1)
+	return s.Source.GetPackageVersions(pkg) //nolint:wrapcheck
+}
+
+func TestSolution_WhyNot(t *testing.T) {
+	t.Parallel()
+
+	source := &countingSource{
+		Source: mockSource{
+			packages: map[string][]PackageVersion{
+				"$$root$$": {
+					{
+						Version: newVersion("1.0.0"),
+						Dependencies: map[string]semver.Constraint{
+							"bar": newConstraint("^1.0.0"),
+							"foo": newConstraint("*"),
+						},
+					},
+				},
+				"foo": {
+					{
+						Version: newVersion("1.3.0"),
+					},
+					{
+						Version: newVersion("1.4.0"),
+					},
+					{
+						Version: newVersion("2.1.0"),
+						Dependencies: map[string]semver.Constraint{
+							"bar": newConstraint("^2.0.0"),
+						},
+					},
+				},
+				"bar": {
+					{
+						Version: newVersion("1.0.0"),
+					},
+					{
+						Version: newVersion("2.0.0"),
+					},
+				},
+			},
+		},
+	}
+
+	solution, err := SolveWithOptions(source, "$$root$$", Options{})
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, newVersion("1.4.0"), solution.Versions["foo"])
+	calls := source.calls.Load()
+
+	newer, err := solution.WhyNot("foo", newVersion("2.1.0"))
+	testza.AssertNoError(t, err)
+	testza.AssertNil(t, newer.Solution)
+	expected := "Because installing foo \"2.1.0\" and foo \">=2.1.0\" depends on bar \"^2.0.0\", installing bar \"^2.0.0\".\nSo, because installing bar \"^1.0.0\", version solving failed."
+	testza.AssertEqual(t, expected, newer.Conflict.Error())
+
+	older, err := solution.WhyNot("foo", newVersion("1.3.0"))
+	testza.AssertNoError(t, err)
+	testza.AssertNil(t, older.Conflict)
+	testza.AssertEqual(t, newVersion("1.3.0"), older.Solution.Versions["foo"])
+
+	testza.AssertEqual(t, calls, source.calls.Load())
+}
+
+func TestSolution_WhyNot_NoSource(t *testing.T) {
+	t.Parallel()
+
+	solution := Solution{
+		Root:     "$$root$$",
+		Versions: map[string]semver.Version{"foo": newVersion("1.0.0")},
+	}
+	_, err := solution.WhyNot("foo", newVersion("2.0.0"))
+	testza.AssertTrue(t, errors.Is(err, ErrNoSource))
+}